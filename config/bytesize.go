@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes, that can be loaded from human-readable values such as "512KB" or "10MiB".
+type ByteSize uint64
+
+// Decimal and binary byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+}
+
+// Units used to format byte sizes, from the largest to the smallest.
+var byteSizeFormats = []struct {
+	unit ByteSize
+	name string
+}{
+	{TiB, "TiB"},
+	{GiB, "GiB"},
+	{MiB, "MiB"},
+	{KiB, "KiB"},
+	{TB, "TB"},
+	{GB, "GB"},
+	{MB, "MB"},
+	{KB, "KB"},
+}
+
+// ByteSizeParser is a parsing function for LoadEnv, that returns the ByteSize representation of the variable.
+// Units are case-insensitive, and a value without unit is a number of bytes, e.g. "1.5GB", "10MiB" or "512".
+func ByteSizeParser(value string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(value)
+
+	numberEnd := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if numberEnd == -1 {
+		numberEnd = len(trimmed)
+	}
+
+	number := trimmed[:numberEnd]
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(trimmed[numberEnd:]))]
+	if !ok || number == "" {
+		return 0, fmt.Errorf(`invalid byte size "%s"`, value)
+	}
+
+	if !strings.Contains(number, ".") {
+		count, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		high, low := bits.Mul64(count, uint64(unit))
+		if high != 0 {
+			return 0, fmt.Errorf(`byte size "%s" is too large`, value)
+		}
+
+		return ByteSize(low), nil
+	}
+
+	count, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	size := count * float64(unit)
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf(`byte size "%s" is too large`, value)
+	}
+
+	return ByteSize(size), nil
+}
+
+// String formats the size with the largest unit that represents it exactly, e.g. "10MiB" or "1500B".
+func (size ByteSize) String() string {
+	for _, format := range byteSizeFormats {
+		if size >= format.unit && size%format.unit == 0 {
+			return strconv.FormatUint(uint64(size/format.unit), 10) + format.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(size), 10) + "B"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (size ByteSize) MarshalText() ([]byte, error) {
+	return []byte(size.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (size *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := ByteSizeParser(string(text))
+	if err != nil {
+		return err
+	}
+
+	*size = parsed
+
+	return nil
+}
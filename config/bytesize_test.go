@@ -0,0 +1,50 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+func TestByteSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+
+		value string
+
+		expect    config.ByteSize
+		expectStr string
+		expectErr bool
+	}{
+		{name: "Bytes", value: "512", expect: 512, expectStr: "512B"},
+		{name: "BytesUnit", value: "1500B", expect: 1500, expectStr: "1500B"},
+		{name: "Decimal", value: "1.5GB", expect: 1500 * config.MB, expectStr: "1500MB"},
+		{name: "Binary", value: "10MiB", expect: 10 * config.MiB, expectStr: "10MiB"},
+		{name: "CaseInsensitive", value: "2 kib", expect: 2 * config.KiB, expectStr: "2KiB"},
+		{name: "UnknownUnit", value: "10PB", expectErr: true},
+		{name: "NoNumber", value: "MiB", expectErr: true},
+		{name: "Negative", value: "-1KB", expectErr: true},
+		{name: "Overflow", value: "100000000TiB", expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			size, err := config.ByteSizeParser(testCase.value)
+			if testCase.expectErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, size)
+			require.Equal(t, testCase.expectStr, size.String())
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FlagSource is a Source that reads values from command-line flags. Place it last in WithSources, so flags take
+// precedence over the other sources.
+//
+//	flagSet := flag.NewFlagSet("migrate", flag.ExitOnError)
+//
+//	flags, err := config.NewFlagSource(flagSet, &cfg)
+//	if err != nil {
+//		return err
+//	}
+//
+//	_ = flagSet.Parse(os.Args[1:])
+//
+//	err = config.LoadStruct(&cfg, config.WithSources(fileSource, &config.EnvSource{}, flags))
+type FlagSource struct {
+	// Names of the flags, by variable.
+	names map[string]string
+	// Values of the flags that were set on the command line, by flag name.
+	values map[string]string
+	mu     sync.RWMutex
+}
+
+var _ Source = (*FlagSource)(nil)
+
+// NewFlagSource defines a flag for every variable of a configuration struct, and returns a Source for their values.
+// Flag names are derived from the variables, without the prefix set by WithPrefix, e.g. --postgres-dsn for
+// POSTGRES_DSN. The usage of each flag is built from the desc tag of the field, so flag.PrintDefaults, and
+// therefore --help, documents the configuration.
+//
+// Values are parsed when the struct is loaded, with the same parser as the other sources.
+func NewFlagSource(flagSet *flag.FlagSet, dst any, options ...StructOption) (*FlagSource, error) {
+	variables, err := Describe(dst, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := newStructOptions(options).prefix
+	source := &FlagSource{
+		names:  make(map[string]string, len(variables)),
+		values: make(map[string]string, len(variables)),
+	}
+
+	for _, variable := range variables {
+		name := FlagName(strings.TrimPrefix(variable.Name, prefix))
+		source.names[variable.Name] = name
+
+		flagSet.Var(&rawFlag{
+			source:   source,
+			name:     name,
+			fallback: variable.Default,
+			isBool:   variable.Type == "bool" || variable.Type == "*bool",
+		}, name, flagUsage(variable))
+	}
+
+	return source, nil
+}
+
+// Name implements Source.
+func (source *FlagSource) Name() string {
+	return "flag"
+}
+
+// Load implements Source.
+func (source *FlagSource) Load() error {
+	return nil
+}
+
+// Lookup implements Source. Only flags set on the command line are returned. Like EnvSource, empty values, e.g.
+// --level=, are treated as unset.
+func (source *FlagSource) Lookup(key Key) (string, bool) {
+	name, ok := source.names[key.Env]
+	if !ok {
+		return "", false
+	}
+
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	value, ok := source.values[name]
+
+	return value, ok && value != ""
+}
+
+// FlagName converts the name of a variable to the name of a flag, e.g. "postgres-dsn" for POSTGRES_DSN.
+func FlagName(env string) string {
+	return strings.ToLower(strings.ReplaceAll(env, "_", "-"))
+}
+
+// FlagVar defines a flag with the given name, default value and usage, that is parsed with a parser from this
+// package. The returned pointer holds the value of the flag once the flag set is parsed.
+//
+//	timeout := config.FlagVar(flagSet, "timeout", 30*time.Second, "Timeout of the job.", config.DurationParser)
+func FlagVar[T any](
+	flagSet *flag.FlagSet, name string, value T, usage string, parser func(string) (T, error),
+) *T {
+	flagValue := &parserFlag[T]{value: value, parser: parser}
+	flagSet.Var(flagValue, name, usage)
+
+	return &flagValue.value
+}
+
+// flagUsage documents a variable in the help of a flag.
+func flagUsage(variable *Variable) string {
+	details := []string{"env " + variable.Name}
+
+	if len(variable.Enum) > 0 {
+		details = append(details, "one of: "+strings.Join(variable.Enum, ", "))
+	}
+
+	if variable.Required {
+		details = append(details, "required")
+	}
+
+	usage := "(" + strings.Join(details, "; ") + ")"
+	if variable.Description != "" {
+		usage = variable.Description + " " + usage
+	}
+
+	return usage
+}
+
+// rawFlag stores the raw value of a flag, to be parsed when the configuration is loaded.
+type rawFlag struct {
+	source   *FlagSource
+	name     string
+	fallback string
+	isBool   bool
+}
+
+func (value *rawFlag) String() string {
+	if value == nil || value.source == nil {
+		return ""
+	}
+
+	value.source.mu.RLock()
+	defer value.source.mu.RUnlock()
+
+	if raw, ok := value.source.values[value.name]; ok {
+		return raw
+	}
+
+	return value.fallback
+}
+
+func (value *rawFlag) Set(raw string) error {
+	value.source.mu.Lock()
+	defer value.source.mu.Unlock()
+
+	value.source.values[value.name] = raw
+
+	return nil
+}
+
+// IsBoolFlag allows boolean flags to be set without a value, e.g. --debug.
+func (value *rawFlag) IsBoolFlag() bool {
+	return value.isBool
+}
+
+// parserFlag is a flag.Value that uses a parser from this package.
+type parserFlag[T any] struct {
+	value  T
+	parser func(string) (T, error)
+}
+
+func (value *parserFlag[T]) String() string {
+	if value == nil || value.parser == nil {
+		return ""
+	}
+
+	return formatValue(reflect.ValueOf(&value.value).Elem(), Key{})
+}
+
+func (value *parserFlag[T]) Set(raw string) error {
+	parsed, err := value.parser(raw)
+	if err != nil {
+		return err
+	}
+
+	value.value = parsed
+
+	return nil
+}
+
+// IsBoolFlag allows boolean flags to be set without a value, e.g. --debug.
+func (value *parserFlag[T]) IsBoolFlag() bool {
+	_, ok := any(value.value).(bool)
+
+	return ok
+}
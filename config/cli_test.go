@@ -0,0 +1,154 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+type cliTestConfig struct {
+	Port    int           `default:"8080"   desc:"Server port." env:"PORT"     json:"port"`
+	Timeout time.Duration `default:"5s"     env:"TIMEOUT"       json:"timeout"`
+	Level   string        `default:"info"   enum:"debug,info"   env:"LEVEL"    json:"level"`
+	Debug   bool          `env:"DEBUG"      json:"debug"`
+	DSN     string        `desc:"Database." env:"POSTGRES_DSN"  json:"dsn"     required:"true" secret:"true"`
+}
+
+func TestFlagSource(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"config.json": {Data: []byte(`{"port": 3000, "timeout": "10s", "level": "debug", "dsn": "postgres://file"}`)},
+	}
+
+	env := map[string]string{"APP_TIMEOUT": "20s", "APP_LEVEL": "info"}
+
+	t.Run("Precedence", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg cliTestConfig
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		flags, err := config.NewFlagSource(flagSet, &cfg, config.WithPrefix("APP_"))
+		require.NoError(t, err)
+		require.NoError(t, flagSet.Parse([]string{"--timeout", "30s", "--debug", "--postgres-dsn=postgres://flag"}))
+
+		fields, err := config.Resolve(&cfg, config.WithPrefix("APP_"), config.WithSources(
+			&config.FileSource{Path: "config.json", FS: files},
+			&config.MapSource{SourceName: "env", Values: env},
+			flags,
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, cliTestConfig{
+			Port:    3000,
+			Timeout: 30 * time.Second,
+			Level:   "info",
+			Debug:   true,
+			DSN:     "postgres://flag",
+		}, cfg)
+
+		origins := make(map[string]string, len(fields))
+		for _, field := range fields {
+			origins[field.Path] = field.Source
+		}
+
+		require.Equal(t, map[string]string{
+			"Port":    "file:config.json",
+			"Timeout": "flag",
+			"Level":   "env",
+			"Debug":   "flag",
+			"DSN":     "flag",
+		}, origins)
+	})
+
+	t.Run("EmptyValue", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg cliTestConfig
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		flags, err := config.NewFlagSource(flagSet, &cfg, config.WithPrefix("APP_"))
+		require.NoError(t, err)
+		require.NoError(t, flagSet.Parse([]string{"--level=", "--postgres-dsn="}))
+
+		// Empty flags are treated as unset, like empty environment variables.
+		err = config.LoadStruct(&cfg, config.WithPrefix("APP_"), config.WithSources(
+			&config.FileSource{Path: "config.json", FS: files},
+			&config.EnvSource{LookupEnv: mapLookup(map[string]string{"APP_LEVEL": ""})},
+			flags,
+		))
+		require.NoError(t, err)
+		require.Equal(t, "debug", cfg.Level)
+		require.Equal(t, "postgres://file", cfg.DSN)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg cliTestConfig
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		flags, err := config.NewFlagSource(flagSet, &cfg)
+		require.NoError(t, err)
+		require.NoError(t, flagSet.Parse([]string{"--level", "trace", "--postgres-dsn", "postgres://flag"}))
+
+		err = config.LoadStruct(&cfg, config.WithSources(flags))
+		require.Len(t, config.VarErrors(err), 1)
+		require.Equal(t, "LEVEL", config.VarErrors(err)[0].Name)
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			cfg  cliTestConfig
+			help bytes.Buffer
+		)
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		flagSet.SetOutput(&help)
+
+		_, err := config.NewFlagSource(flagSet, &cfg)
+		require.NoError(t, err)
+		require.ErrorIs(t, flagSet.Parse([]string{"--help"}), flag.ErrHelp)
+
+		require.Contains(t, help.String(), "-port value\n    \tServer port. (env PORT) (default 8080)\n")
+		require.Contains(t, help.String(), "-level value\n    \t(env LEVEL; one of: debug, info) (default info)\n")
+		require.Contains(t, help.String(), "-debug\n    \t(env DEBUG)\n")
+		require.Contains(t, help.String(), "-postgres-dsn value\n    \tDatabase. (env POSTGRES_DSN; required)\n")
+	})
+}
+
+func TestFlagVar(t *testing.T) {
+	t.Parallel()
+
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	timeout := config.FlagVar(flagSet, "timeout", 5*time.Second, "Timeout.", config.DurationParser)
+	origins := config.FlagVar(flagSet, "origins", []string{"a.com"}, "Origins.", config.SliceParser(config.StringParser))
+	level := config.FlagVar(flagSet, "level", "info", "Level.", config.EnumParser(config.StringParser, "debug", "info"))
+	dryRun := config.FlagVar(flagSet, "dry-run", false, "Dry run.", config.BoolParser)
+
+	require.Equal(t, "5s", flagSet.Lookup("timeout").DefValue)
+	require.Equal(t, "a.com", flagSet.Lookup("origins").DefValue)
+
+	require.NoError(t, flagSet.Parse([]string{"--timeout=1m", "--origins", "b.com,c.com", "--dry-run"}))
+	require.Equal(t, time.Minute, *timeout)
+	require.Equal(t, []string{"b.com", "c.com"}, *origins)
+	require.Equal(t, "info", *level)
+	require.True(t, *dryRun)
+
+	require.Error(t, flagSet.Parse([]string{"--level", "trace"}))
+}
@@ -0,0 +1,323 @@
+package config
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Variable documents a field of a configuration struct, as returned by Describe.
+type Variable struct {
+	// Path of the field in the root struct, e.g. "Postgres.DSN".
+	Path string
+	// Name of the variable, including prefixes.
+	Name string
+	// Type of the field, e.g. "time.Duration".
+	Type string
+	// Default value of the field, from its default tag, or from the value passed to Describe. Secrets are Redacted.
+	Default string
+	// Required is true if the field is tagged as required.
+	Required bool
+	// Secret is true if the field is tagged as secret.
+	Secret bool
+	// Enum lists the values allowed by the enum tag.
+	Enum []string
+	// Description of the field, from its desc tag.
+	Description string
+}
+
+// Describe lists the variables of a configuration struct, without loading them. It accepts a struct, or a pointer
+// to a struct, whose current values are reported as defaults when fields have no default tag.
+//
+// Only WithPrefix is used among the options.
+func Describe(dst any, options ...StructOption) ([]*Variable, error) {
+	target := reflect.ValueOf(dst)
+	if target.Kind() == reflect.Pointer && !target.IsNil() {
+		target = target.Elem()
+	}
+
+	if target.Kind() != reflect.Struct {
+		return nil, ErrInvalidTarget
+	}
+
+	// Work on an addressable copy, as dst may be passed by value.
+	value := reflect.New(target.Type()).Elem()
+	value.Set(target)
+
+	var variables []*Variable
+
+	err := walkStruct(value, walkScope{prefix: newStructOptions(options).prefix}, func(field *structField) error {
+		variable := &Variable{
+			Path:        field.path,
+			Name:        field.key.Env,
+			Type:        field.key.Type.String(),
+			Required:    field.required,
+			Secret:      field.secret,
+			Enum:        field.enum,
+			Description: field.desc,
+		}
+
+		if field.key.Encoding != "" {
+			variable.Type += " (" + field.key.Encoding + ")"
+		}
+
+		switch {
+		case field.hasDef:
+			variable.Default = field.def
+		case !field.value.IsZero():
+			variable.Default = formatValue(field.value, field.key)
+		}
+
+		if variable.Secret && variable.Default != "" {
+			variable.Default = Redacted
+		}
+
+		variables = append(variables, variable)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// WriteTable writes the effective configuration returned by Resolve as a text table, with the name, value and
+// source of each variable. Secrets are redacted.
+func WriteTable(w io.Writer, fields []*Field) error {
+	var builder strings.Builder
+
+	table := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+
+	_, _ = table.Write([]byte("VARIABLE\tVALUE\tSOURCE\n"))
+	for _, field := range fields {
+		_, _ = table.Write([]byte(
+			field.Key.Env + "\t" + tableCell(field.Value) + "\t" + cmp.Or(field.Source, "-") + "\n",
+		))
+	}
+
+	_ = table.Flush()
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// WriteJSON writes the effective configuration returned by Resolve as a JSON array. Secrets are redacted.
+func WriteJSON(w io.Writer, fields []*Field) error {
+	type jsonField struct {
+		Path   string `json:"path"`
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Source string `json:"source,omitempty"`
+		Secret bool   `json:"secret,omitempty"`
+	}
+
+	output := make([]jsonField, len(fields))
+	for i, field := range fields {
+		output[i] = jsonField{
+			Path:   field.Path,
+			Name:   field.Key.Env,
+			Value:  field.Value,
+			Source: field.Source,
+			Secret: field.Secret,
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(output)
+}
+
+// WriteEnvExample writes a reference .env file for the variables returned by Describe. Each variable is preceded
+// by its description, and set to its default value.
+func WriteEnvExample(w io.Writer, variables []*Variable) error {
+	var builder strings.Builder
+
+	for i, variable := range variables {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		for line := range strings.Lines(variable.Description) {
+			builder.WriteString("# " + strings.TrimRight(line, "\n") + "\n")
+		}
+
+		builder.WriteString("# " + strings.Join(variableDetails(variable), ", ") + "\n")
+
+		defaultValue := variable.Default
+		if variable.Secret {
+			defaultValue = ""
+		}
+
+		if strings.ContainsAny(defaultValue, " #\"'\\\n\t") {
+			defaultValue = strconv.Quote(defaultValue)
+		}
+
+		builder.WriteString(variable.Name + "=" + defaultValue + "\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// WriteMarkdown writes a Markdown table documenting the variables returned by Describe.
+func WriteMarkdown(w io.Writer, variables []*Variable) error {
+	var builder strings.Builder
+
+	builder.WriteString("| Variable | Type | Default | Required | Description |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, variable := range variables {
+		defaultValue := ""
+		if variable.Default != "" {
+			defaultValue = "`" + variable.Default + "`"
+		}
+
+		required := ""
+		if variable.Required {
+			required = "yes"
+		}
+
+		description := strings.ReplaceAll(variable.Description, "\n", " ")
+		if len(variable.Enum) > 0 {
+			description = strings.TrimSpace(description + " One of: `" + strings.Join(variable.Enum, "`, `") + "`.")
+		}
+
+		cells := []string{"`" + variable.Name + "`", "`" + variable.Type + "`", defaultValue, required, description}
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// variableDetails lists the type and constraints of a variable, for WriteEnvExample.
+func variableDetails(variable *Variable) []string {
+	details := []string{"type: " + variable.Type}
+
+	if len(variable.Enum) > 0 {
+		details = append(details, "one of: "+strings.Join(variable.Enum, " | "))
+	}
+
+	if variable.Required {
+		details = append(details, "required")
+	}
+
+	if variable.Secret {
+		details = append(details, "secret")
+	}
+
+	return details
+}
+
+// tableCell quotes values that would break the layout of a text table.
+func tableCell(value string) string {
+	if strings.ContainsAny(value, "\t\n\r") {
+		return strconv.Quote(value)
+	}
+
+	return cmp.Or(value, `""`)
+}
+
+// formatValue formats the value of a field, in a format its parser accepts when possible.
+func formatValue(value reflect.Value, key Key) string {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+
+		return formatValue(value.Elem(), key)
+	}
+
+	switch {
+	case key.Encoding == "json":
+		encoded, _ := jsonString(value.Interface())
+
+		return encoded
+	case key.Encoding == "base64" && value.Type() == bytesType:
+		return base64.StdEncoding.EncodeToString(value.Bytes())
+	case key.Encoding == "hex" && value.Type() == bytesType:
+		return hex.EncodeToString(value.Bytes())
+	}
+
+	if formatted, ok := formatText(value); ok {
+		return formatted
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		if _, ok := typeParsers[value.Type()]; ok {
+			encoded, _ := jsonString(value.Interface())
+
+			return encoded
+		}
+
+		parts := make([]string, value.Len())
+		for i := range value.Len() {
+			parts[i] = formatValue(value.Index(i), Key{Encoding: key.Encoding})
+		}
+
+		return strings.Join(parts, separator(key.Sep))
+	case reflect.Map:
+		if _, ok := typeParsers[value.Type()]; ok {
+			encoded, _ := jsonString(value.Interface())
+
+			return encoded
+		}
+
+		sep, kvSep := separator(key.Sep), kvSeparator(key.KVSep)
+		pairs := make([]string, 0, value.Len())
+
+		for iter := value.MapRange(); iter.Next(); {
+			name := quote(formatValue(iter.Key(), Key{}), sep, kvSep)
+			elem := quote(formatValue(iter.Value(), Key{}), sep, kvSep)
+			pairs = append(pairs, name+kvSep+elem)
+		}
+
+		slices.Sort(pairs)
+
+		return strings.Join(pairs, sep)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// formatText formats values that implement encoding.TextMarshaler or fmt.Stringer.
+func formatText(value reflect.Value) (string, bool) {
+	candidates := []any{value.Interface()}
+	if value.CanAddr() {
+		candidates = append(candidates, value.Addr().Interface())
+	}
+
+	for _, candidate := range candidates {
+		switch typed := candidate.(type) {
+		case encoding.TextMarshaler:
+			text, err := typed.MarshalText()
+			if err == nil {
+				return string(text), true
+			}
+		case fmt.Stringer:
+			return typed.String(), true
+		}
+	}
+
+	return "", false
+}
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -26,14 +32,19 @@ func LoadEnv[T any](value string, fallback T, parser func(string) (T, error)) T
 // SliceParser is a parsing function for LoadEnv, that returns a slice of values of type T.
 // The parser accepts a sub-parser to define the type of T.
 func SliceParser[T any](parser func(string) (T, error)) func(string) ([]T, error) {
+	return SliceParserSep(",", parser)
+}
+
+// SliceParserSep works like SliceParser, but splits the value on a custom separator instead of commas.
+func SliceParserSep[T any](sep string, parser func(string) (T, error)) func(string) ([]T, error) {
 	return func(value string) ([]T, error) {
 		// To force an empty slice if the value is empty.
 		if value == "[]" {
 			return nil, nil
 		}
 
-		// Split source on commas.
-		parts := strings.Split(value, ",")
+		// Split source on the separator.
+		parts := strings.Split(value, sep)
 
 		// Parse each part using the provided parser.
 		parsedValues := make([]T, 0, len(parts))
@@ -62,11 +73,96 @@ func SliceParser[T any](parser func(string) (T, error)) func(string) ([]T, error
 	}
 }
 
+// MapParser is a parsing function for LoadEnv, that returns a map of keys of type K to values of type V, from
+// a list of key=value pairs separated by commas, e.g. "/login=5,/signup=1". Keys and values can be wrapped in double
+// quotes, or use backslashes to escape separators, e.g. `motd="hello, world"`.
+// The parser accepts sub-parsers to define the types of K and V.
+func MapParser[K comparable, V any](
+	keyParser func(string) (K, error), valueParser func(string) (V, error),
+) func(string) (map[K]V, error) {
+	return MapParserSep(",", "=", keyParser, valueParser)
+}
+
+// MapParserSep works like MapParser, but uses custom separators between pairs, and between keys and values.
+func MapParserSep[K comparable, V any](
+	sep, kvSep string, keyParser func(string) (K, error), valueParser func(string) (V, error),
+) func(string) (map[K]V, error) {
+	return func(value string) (map[K]V, error) {
+		// To force an empty map if the value is empty.
+		if value == "{}" {
+			return map[K]V{}, nil
+		}
+
+		pairs, err := splitQuoted(value, sep, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedValues := make(map[K]V, len(pairs))
+
+		for _, pair := range pairs {
+			if strings.TrimSpace(pair) == "" {
+				continue // Skip empty pairs.
+			}
+
+			parts, err := splitQuoted(pair, kvSep, 2)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(parts) != 2 {
+				return nil, fmt.Errorf(`pair "%s" has no "%s" separator`, strings.TrimSpace(pair), kvSep)
+			}
+
+			parsedKey, err := keyParser(unquote(parts[0]))
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := parsedValues[parsedKey]; ok {
+				return nil, fmt.Errorf(`duplicate key "%s"`, strings.TrimSpace(parts[0]))
+			}
+
+			parsedValue, err := valueParser(unquote(parts[1]))
+			if err != nil {
+				return nil, err
+			}
+
+			parsedValues[parsedKey] = parsedValue
+		}
+
+		// If no pairs were parsed, return the fallback.
+		if len(parsedValues) == 0 {
+			return nil, fmt.Errorf(`value "%s" is empty`, value)
+		}
+
+		return parsedValues, nil
+	}
+}
+
+// JSONParser is a parsing function for LoadEnv, that unmarshals the variable into a value of type T.
+//
+//	limits := config.LoadEnv(os.Getenv("RATE_LIMITS"), nil, config.JSONParser[map[string]RateLimit])
+func JSONParser[T any](value string) (T, error) {
+	var parsedValue T
+
+	err := json.Unmarshal([]byte(value), &parsedValue)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return parsedValue, nil
+}
+
 // StringParser is a parsing function for LoadEnv, that returns the string content of the variable, as-is.
 func StringParser(value string) (string, error) {
 	return value, nil
 }
 
+// EnumParser is a parsing function for LoadEnv, that only accepts values from a predefined list.
+// The parser accepts a sub-parser to define the type of T.
 func EnumParser[T comparable](parser func(string) (T, error), allow ...T) func(string) (T, error) {
 	return func(value string) (T, error) {
 		raw, err := parser(value)
@@ -240,6 +336,112 @@ func TimeParser(value string) (time.Time, error) {
 	return parsedValue, nil
 }
 
+// TimeLayoutParser is a parsing function for LoadEnv, that returns the time.Time representation of the variable,
+// using a custom layout. For more information about supported layouts, refer to the documentation of time.Parse.
+func TimeLayoutParser(layout string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	}
+}
+
+// UnixTimeParser is a parsing function for LoadEnv, that returns the time.Time representation of a Unix timestamp,
+// in seconds. Fractional seconds are supported, e.g. "1700000000.5".
+func UnixTimeParser(value string) (time.Time, error) {
+	seconds, fraction, hasFraction := strings.Cut(value, ".")
+
+	parsedSeconds, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var nanoseconds int64
+
+	if hasFraction {
+		if fraction == "" || len(fraction) > 9 {
+			return time.Time{}, fmt.Errorf(`invalid fractional seconds in "%s"`, value)
+		}
+
+		nanoseconds, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if strings.HasPrefix(seconds, "-") {
+			nanoseconds = -nanoseconds
+		}
+	}
+
+	return time.Unix(parsedSeconds, nanoseconds).UTC(), nil
+}
+
+// URLParser is a parsing function for LoadEnv, that returns the *url.URL representation of the variable.
+// The URL must be absolute. For more information about supported value, refer to the documentation of url.Parse.
+func URLParser(value string) (*url.URL, error) {
+	parsedValue, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsedValue.Scheme == "" {
+		return nil, fmt.Errorf(`url "%s" is not absolute`, value)
+	}
+
+	return parsedValue, nil
+}
+
+// IPParser is a parsing function for LoadEnv, that returns the netip.Addr representation of the variable.
+// For more information about supported value, refer to the documentation of netip.ParseAddr.
+func IPParser(value string) (netip.Addr, error) {
+	return netip.ParseAddr(value)
+}
+
+// PrefixParser is a parsing function for LoadEnv, that returns the netip.Prefix representation of a CIDR, e.g.
+// "10.0.0.0/8". For more information about supported value, refer to the documentation of netip.ParsePrefix.
+func PrefixParser(value string) (netip.Prefix, error) {
+	return netip.ParsePrefix(value)
+}
+
+// HostPortParser is a parsing function for LoadEnv, that only accepts addresses in the "host:port" format. The
+// host may be empty, e.g. ":8080".
+func HostPortParser(value string) (string, error) {
+	err := IsHostPort(value)
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// RegexpParser is a parsing function for LoadEnv, that compiles the variable as a regular expression.
+// For more information about supported value, refer to the documentation of regexp.Compile.
+func RegexpParser(value string) (*regexp.Regexp, error) {
+	return regexp.Compile(value)
+}
+
+// LogLevelParser is a parsing function for LoadEnv, that returns the slog.Level representation of the variable,
+// e.g. "debug" or "WARN+2". For more information about supported value, refer to the documentation of
+// slog.Level.UnmarshalText.
+func LogLevelParser(value string) (slog.Level, error) {
+	var parsedValue slog.Level
+
+	err := parsedValue.UnmarshalText([]byte(value))
+	if err != nil {
+		return 0, err
+	}
+
+	return parsedValue, nil
+}
+
+// Base64Parser is a parsing function for LoadEnv, that decodes a standard base64 variable. Padding is optional.
+func Base64Parser(value string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// HexParser is a parsing function for LoadEnv, that decodes a hexadecimal variable.
+func HexParser(value string) ([]byte, error) {
+	return hex.DecodeString(value)
+}
+
 // JSONMapParser is a parsing function for LoadEnv, that returns the map[string]any representation of the variable.
 // The parser expects the value to be a valid JSON object.
 func JSONMapParser(value string) (map[string]any, error) {
@@ -265,3 +467,75 @@ func JSONSliceParser(value string) ([]any, error) {
 
 	return parsedValue, nil
 }
+
+// splitQuoted splits value around sep, ignoring separators wrapped in double quotes or escaped with a backslash.
+// Parts are returned as-is, with their quotes and escape characters. The count limits the number of parts, as in
+// strings.SplitN.
+func splitQuoted(value, sep string, count int) ([]string, error) {
+	var (
+		parts    []string
+		start    int
+		inQuotes bool
+	)
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\':
+			if i == len(value)-1 {
+				return nil, fmt.Errorf(`value "%s" ends with an escape character`, value)
+			}
+
+			i++ // Skip the escaped character.
+		case value[i] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && strings.HasPrefix(value[i:], sep) && len(parts) != count-1:
+			parts = append(parts, value[start:i])
+			start = i + len(sep)
+			i = start - 1
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf(`value "%s" has an unterminated quote`, value)
+	}
+
+	return append(parts, value[start:]), nil
+}
+
+// unquote trims the spaces around a part returned by splitQuoted, and removes its quotes and escape characters.
+func unquote(part string) string {
+	var (
+		builder strings.Builder
+		escaped bool
+	)
+
+	for _, char := range strings.TrimSpace(part) {
+		switch {
+		case escaped:
+			builder.WriteRune(char)
+
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char != '"':
+			builder.WriteRune(char)
+		}
+	}
+
+	return builder.String()
+}
+
+// quote escapes a key or value for MapParserSep, if it contains special characters.
+func quote(part string, separators ...string) string {
+	special := strings.ContainsAny(part, `"\`) || strings.TrimSpace(part) != part
+
+	for _, sep := range separators {
+		special = special || strings.Contains(part, sep)
+	}
+
+	if !special {
+		return part
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(part) + `"`
+}
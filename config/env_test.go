@@ -1,7 +1,11 @@
 package config_test
 
 import (
+	"log/slog"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
 	"testing"
 	"time"
 
@@ -155,6 +159,129 @@ func TestEnvTimeParser(t *testing.T) {
 	require.Equal(t, now, config.LoadEnv(os.Getenv("qux"), now, config.TimeParser))
 }
 
+func TestEnvTimeLayoutParser(t *testing.T) {
+	t.Setenv("foo", "2020-01-02")
+	t.Setenv("bar", "2020-01-02T15:04:05Z")
+
+	now := time.Now()
+	custom := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), now, config.TimeLayoutParser(time.DateOnly)))
+	require.Equal(t, now, config.LoadEnv(os.Getenv("bar"), now, config.TimeLayoutParser(time.DateOnly)))
+	require.Equal(t, now, config.LoadEnv(os.Getenv("qux"), now, config.TimeLayoutParser(time.DateOnly)))
+}
+
+func TestEnvUnixTimeParser(t *testing.T) {
+	t.Setenv("foo", "1577977445")
+	t.Setenv("bar", "1577977445.25")
+	t.Setenv("baz", "bar:value")
+
+	now := time.Now()
+
+	custom := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), now, config.UnixTimeParser))
+	require.Equal(t, custom.Add(250*time.Millisecond), config.LoadEnv(os.Getenv("bar"), now, config.UnixTimeParser))
+	require.Equal(t, now, config.LoadEnv(os.Getenv("baz"), now, config.UnixTimeParser))
+	require.Equal(t, now, config.LoadEnv(os.Getenv("qux"), now, config.UnixTimeParser))
+}
+
+func TestEnvURLParser(t *testing.T) {
+	t.Setenv("foo", "https://example.com/path?query=1")
+	t.Setenv("bar", "/relative/path")
+
+	basic := &url.URL{Scheme: "http", Host: "localhost"}
+	custom := &url.URL{Scheme: "https", Host: "example.com", Path: "/path", RawQuery: "query=1"}
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, config.URLParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.URLParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.URLParser))
+}
+
+func TestEnvIPParser(t *testing.T) {
+	t.Setenv("foo", "::1")
+	t.Setenv("bar", "bar:value")
+
+	basic := netip.MustParseAddr("127.0.0.1")
+
+	require.Equal(t, netip.IPv6Loopback(), config.LoadEnv(os.Getenv("foo"), basic, config.IPParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.IPParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.IPParser))
+}
+
+func TestEnvPrefixParser(t *testing.T) {
+	t.Setenv("foo", "10.0.0.0/8")
+	t.Setenv("bar", "10.0.0.1")
+
+	basic := netip.MustParsePrefix("192.168.0.0/16")
+	custom := netip.MustParsePrefix("10.0.0.0/8")
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, config.PrefixParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.PrefixParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.PrefixParser))
+}
+
+func TestEnvHostPortParser(t *testing.T) {
+	t.Setenv("foo", "smtp.example.com:587")
+	t.Setenv("bar", "smtp.example.com")
+
+	require.Equal(t, "smtp.example.com:587", config.LoadEnv(os.Getenv("foo"), ":25", config.HostPortParser))
+	require.Equal(t, ":25", config.LoadEnv(os.Getenv("bar"), ":25", config.HostPortParser))
+	require.Equal(t, ":25", config.LoadEnv(os.Getenv("qux"), ":25", config.HostPortParser))
+}
+
+func TestEnvByteSizeParser(t *testing.T) {
+	t.Setenv("foo", "10MiB")
+	t.Setenv("bar", "10 parsecs")
+
+	require.Equal(t, 10*config.MiB, config.LoadEnv(os.Getenv("foo"), config.KiB, config.ByteSizeParser))
+	require.Equal(t, config.KiB, config.LoadEnv(os.Getenv("bar"), config.KiB, config.ByteSizeParser))
+	require.Equal(t, config.KiB, config.LoadEnv(os.Getenv("qux"), config.KiB, config.ByteSizeParser))
+}
+
+func TestEnvRegexpParser(t *testing.T) {
+	t.Setenv("foo", "^[0-9]+$")
+	t.Setenv("bar", "[")
+
+	basic := regexp.MustCompile(".*")
+	custom := regexp.MustCompile("^[0-9]+$")
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, config.RegexpParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.RegexpParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.RegexpParser))
+}
+
+func TestEnvLogLevelParser(t *testing.T) {
+	t.Setenv("foo", "debug")
+	t.Setenv("bar", "bar:value")
+
+	require.Equal(t, slog.LevelDebug, config.LoadEnv(os.Getenv("foo"), slog.LevelInfo, config.LogLevelParser))
+	require.Equal(t, slog.LevelInfo, config.LoadEnv(os.Getenv("bar"), slog.LevelInfo, config.LogLevelParser))
+	require.Equal(t, slog.LevelInfo, config.LoadEnv(os.Getenv("qux"), slog.LevelInfo, config.LogLevelParser))
+}
+
+func TestEnvBase64Parser(t *testing.T) {
+	t.Setenv("foo", "c2VjcmV0")
+	t.Setenv("bar", "bar:value")
+
+	basic := []byte("default")
+
+	require.Equal(t, []byte("secret"), config.LoadEnv(os.Getenv("foo"), basic, config.Base64Parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.Base64Parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.Base64Parser))
+}
+
+func TestEnvHexParser(t *testing.T) {
+	t.Setenv("foo", "cafe")
+	t.Setenv("bar", "bar:value")
+
+	basic := []byte{0xbe, 0xef}
+
+	require.Equal(t, []byte{0xca, 0xfe}, config.LoadEnv(os.Getenv("foo"), basic, config.HexParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.HexParser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.HexParser))
+}
+
 func TestEnvJSONMapParser(t *testing.T) {
 	t.Setenv("foo", `{"key":"super-value"}`)
 	t.Setenv("bar", "bar:value")
@@ -189,6 +316,70 @@ func TestEnvStringSliceParser(t *testing.T) {
 	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.SliceParser(config.StringParser)))
 }
 
+func TestEnvMapParser(t *testing.T) {
+	t.Setenv("foo", `/login=5, /signup=1`)
+	t.Setenv("bar", `/login=5,/signup`)
+	t.Setenv("baz", `/login=5,/login=1`)
+
+	basic := map[string]int{"/": 10}
+	custom := map[string]int{"/login": 5, "/signup": 1}
+	parser := config.MapParser(config.StringParser, config.IntParser)
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("baz"), basic, parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, parser))
+}
+
+func TestEnvMapParserQuoted(t *testing.T) {
+	t.Setenv("foo", `motd="hello, world",quote="say \"hi\"",path=a\=b`)
+	t.Setenv("bar", `motd="hello, world`)
+	t.Setenv("baz", `{}`)
+
+	basic := map[string]string{"motd": "hello"}
+	custom := map[string]string{"motd": "hello, world", "quote": `say "hi"`, "path": "a=b"}
+	parser := config.MapParser(config.StringParser, config.StringParser)
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, parser))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, parser))
+	require.Equal(t, map[string]string{}, config.LoadEnv(os.Getenv("baz"), basic, parser))
+}
+
+func TestEnvMapParserSep(t *testing.T) {
+	t.Setenv("foo", "service.name:api;service.version:1.0")
+
+	basic := map[string]string{"service.name": "unknown"}
+	custom := map[string]string{"service.name": "api", "service.version": "1.0"}
+
+	require.Equal(
+		t,
+		custom,
+		config.LoadEnv(os.Getenv("foo"), basic, config.MapParserSep(";", ":", config.StringParser, config.StringParser)),
+	)
+	require.Equal(
+		t,
+		basic,
+		config.LoadEnv(os.Getenv("bar"), basic, config.MapParserSep(";", ":", config.StringParser, config.StringParser)),
+	)
+}
+
+func TestEnvJSONParser(t *testing.T) {
+	type rateLimit struct {
+		Burst  int           `json:"burst"`
+		Period time.Duration `json:"period"`
+	}
+
+	t.Setenv("foo", `{"burst":10,"period":1000000000}`)
+	t.Setenv("bar", `{"burst":"10"}`)
+
+	basic := rateLimit{Burst: 1, Period: time.Minute}
+	custom := rateLimit{Burst: 10, Period: time.Second}
+
+	require.Equal(t, custom, config.LoadEnv(os.Getenv("foo"), basic, config.JSONParser[rateLimit]))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("bar"), basic, config.JSONParser[rateLimit]))
+	require.Equal(t, basic, config.LoadEnv(os.Getenv("qux"), basic, config.JSONParser[rateLimit]))
+}
+
 func TestEnvEnumParser(t *testing.T) {
 	t.Setenv("foo", "bar")
 
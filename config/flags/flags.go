@@ -0,0 +1,209 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/a-novel-kit/golib/config"
+	"github.com/a-novel-kit/golib/otel"
+)
+
+// Subject is the kind of entity flags are evaluated for.
+type Subject string
+
+const (
+	SubjectUser   Subject = "user"
+	SubjectTenant Subject = "tenant"
+)
+
+var (
+	ErrDuplicateFlag = errors.New("duplicate flag")
+	ErrInvalidName   = errors.New("invalid flag name")
+)
+
+type subjectKey struct {
+	subject Subject
+}
+
+type setKey struct{}
+
+// WithSubject returns a context that carries the ID of a subject, used to evaluate flags.
+func WithSubject(ctx context.Context, subject Subject, id string) context.Context {
+	return context.WithValue(ctx, subjectKey{subject: subject}, id)
+}
+
+// SubjectID returns the ID of a subject stored in the context, or an empty string.
+func SubjectID(ctx context.Context, subject Subject) string {
+	id, _ := ctx.Value(subjectKey{subject: subject}).(string)
+
+	return id
+}
+
+// Flag is a named feature flag.
+//
+//	var NewEditor = &flags.Flag{Name: "new_editor", Subject: flags.SubjectUser}
+type Flag struct {
+	// Name of the flag. It is also the key of the flag in structured sources, and, in upper case, the name of its
+	// variable, e.g. NEW_EDITOR for "new_editor".
+	Name string
+	// Subject the flag is evaluated for. Defaults to SubjectUser.
+	Subject Subject
+	// Default rule, used when the flag is not set in any source.
+	Default Rule
+}
+
+// Env returns the name of the variable the flag is loaded from, without prefix.
+func (flag *Flag) Env() string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(flag.Name))
+}
+
+func (flag *Flag) subject() Subject {
+	if flag.Subject == "" {
+		return SubjectUser
+	}
+
+	return flag.Subject
+}
+
+// SetOptions configures a Set.
+type SetOptions struct {
+	// Path of the flags in structured sources. For example, with []string{"flags"}, the rule of the "new_editor"
+	// flag is read from the "flags.new_editor" key of a file.
+	Path []string
+	// Meter used to count evaluations. Defaults to otel.Meter.
+	Meter metric.Meter
+}
+
+// Set holds the rules of a list of flags, loaded from configuration sources.
+//
+//	set, err := flags.NewSet([]*flags.Flag{NewEditor}, flags.SetOptions{Path: []string{"flags"}},
+//		config.WithPrefix("FLAG_"), config.WithSources(fileSource, &config.EnvSource{}))
+//
+//	ctx = flags.NewContext(ctx, set)
+//	ctx = flags.WithSubject(ctx, flags.SubjectUser, userID)
+//
+//	if flags.Enabled(ctx, NewEditor) {
+//		// ...
+//	}
+//
+// Rules can be updated at runtime with Reload, for example from a config.Watcher subscription.
+type Set struct {
+	flags   []*Flag
+	options SetOptions
+	load    []config.StructOption
+
+	rules       atomic.Pointer[map[string]Rule]
+	evaluations metric.Int64Counter
+}
+
+// NewSet loads the rules of the given flags.
+func NewSet(flags []*Flag, options SetOptions, loadOptions ...config.StructOption) (*Set, error) {
+	names := make(map[string]bool, len(flags))
+
+	for _, flag := range flags {
+		if flag.Env() == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidName, flag.Name)
+		}
+
+		if names[flag.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateFlag, flag.Name)
+		}
+
+		names[flag.Name] = true
+	}
+
+	if options.Meter == nil {
+		options.Meter = otel.Meter()
+	}
+
+	evaluations, err := options.Meter.Int64Counter(
+		"feature_flag.evaluations",
+		metric.WithDescription("Number of feature flag evaluations."),
+		metric.WithUnit("{evaluation}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create evaluations counter: %w", err)
+	}
+
+	set := &Set{
+		flags:       slices.Clone(flags),
+		options:     options,
+		load:        loadOptions,
+		evaluations: evaluations,
+	}
+
+	err = set.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// Reload reads the rules from the sources again. If any rule is invalid, the previous rules are kept.
+func (set *Set) Reload() error {
+	loader := config.NewLoader(set.load...)
+	rules := make(map[string]Rule, len(set.flags))
+
+	for _, flag := range set.flags {
+		rules[flag.Name] = config.LoadKey(loader, config.Key{
+			Env:  flag.Env(),
+			Path: append(slices.Clone(set.options.Path), flag.Name),
+		}, flag.Default, RuleParser)
+	}
+
+	err := loader.Err()
+	if err != nil {
+		return fmt.Errorf("load flags: %w", err)
+	}
+
+	set.rules.Store(&rules)
+
+	return nil
+}
+
+// Rule returns the current rule of a flag. Flags that are not part of the set use their default rule.
+func (set *Set) Rule(flag *Flag) Rule {
+	rule, ok := (*set.rules.Load())[flag.Name]
+	if !ok {
+		return flag.Default
+	}
+
+	return rule
+}
+
+// Enabled returns true if the flag is enabled for the subject stored in the context.
+func (set *Set) Enabled(ctx context.Context, flag *Flag) bool {
+	enabled := set.Rule(flag).Match(flag.Name, SubjectID(ctx, flag.subject()))
+
+	set.evaluations.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("feature_flag.key", flag.Name),
+		attribute.String("feature_flag.subject", string(flag.subject())),
+		attribute.Bool("feature_flag.enabled", enabled),
+	))
+
+	return enabled
+}
+
+// NewContext returns a context that carries a Set, used by Enabled.
+func NewContext(ctx context.Context, set *Set) context.Context {
+	return context.WithValue(ctx, setKey{}, set)
+}
+
+// Enabled returns true if the flag is enabled for the subject stored in the context, using the Set stored in the
+// context. If the context carries no Set, the default rule of the flag is used.
+func Enabled(ctx context.Context, flag *Flag) bool {
+	set, ok := ctx.Value(setKey{}).(*Set)
+	if !ok {
+		return flag.Default.Match(flag.Name, SubjectID(ctx, flag.subject()))
+	}
+
+	return set.Enabled(ctx, flag)
+}
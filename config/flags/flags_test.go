@@ -0,0 +1,208 @@
+package flags_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/a-novel-kit/golib/config"
+	"github.com/a-novel-kit/golib/config/flags"
+)
+
+// testMeter counts evaluations by flag and result.
+type testMeter struct {
+	noop.Meter
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+type testCounter struct {
+	noop.Int64Counter
+
+	meter *testMeter
+}
+
+func (meter *testMeter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &testCounter{meter: meter}, nil
+}
+
+func (counter *testCounter) Add(_ context.Context, incr int64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	key, _ := attrs.Value(attribute.Key("feature_flag.key"))
+	enabled, _ := attrs.Value(attribute.Key("feature_flag.enabled"))
+
+	counter.meter.mu.Lock()
+	defer counter.meter.mu.Unlock()
+
+	counter.meter.counts[key.AsString()+"="+enabled.Emit()] += incr
+}
+
+func TestRuleParser(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+
+		value string
+
+		expect    flags.Rule
+		expectErr bool
+	}{
+		{name: "Enabled", value: "true", expect: flags.Rule{Enabled: true}},
+		{name: "Disabled", value: "FALSE", expect: flags.Rule{}},
+		{name: "Percentage", value: "12.5%", expect: flags.Rule{Percentage: 12.5}},
+		{name: "AllowList", value: "alice, bob", expect: flags.Rule{Allow: []string{"alice", "bob"}}},
+		{
+			name:   "Combined",
+			value:  "10%,alice",
+			expect: flags.Rule{Percentage: 10, Allow: []string{"alice"}},
+		},
+		{name: "InvalidPercentage", value: "ten%", expectErr: true},
+		{name: "PercentageOutOfRange", value: "120%", expectErr: true},
+		{name: "Empty", value: " , ", expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := flags.RuleParser(testCase.value)
+			if testCase.expectErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, rule)
+
+			roundTrip, err := flags.RuleParser(rule.String())
+			require.NoError(t, err)
+			require.Equal(t, rule, roundTrip)
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, flags.Rule{Enabled: true}.Match("flag", ""))
+	require.False(t, flags.Rule{Allow: []string{"alice"}}.Match("flag", ""))
+	require.True(t, flags.Rule{Allow: []string{"alice"}}.Match("flag", "alice"))
+	require.False(t, flags.Rule{Allow: []string{"alice"}}.Match("flag", "bob"))
+
+	var enabled int
+
+	for i := range 1000 {
+		id := "user-" + strconv.Itoa(i)
+
+		// Rollouts are stable, and include every subject of smaller rollouts.
+		if (flags.Rule{Percentage: 30}).Match("flag", id) {
+			enabled++
+
+			require.True(t, flags.Rule{Percentage: 30}.Match("flag", id))
+			require.True(t, flags.Rule{Percentage: 60}.Match("flag", id))
+		}
+
+		require.False(t, flags.Rule{}.Match("flag", id))
+		require.True(t, flags.Rule{Percentage: 100}.Match("flag", id))
+	}
+
+	require.InDelta(t, 300, enabled, 50)
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	newEditor := &flags.Flag{Name: "new_editor"}
+	betaAPI := &flags.Flag{Name: "beta-api", Subject: flags.SubjectTenant, Default: flags.Rule{Allow: []string{"acme"}}}
+	darkMode := &flags.Flag{Name: "dark_mode", Default: flags.Rule{Enabled: true}}
+
+	files := fstest.MapFS{
+		"flags.json": {Data: []byte(`{"flags": {"new_editor": ["alice", "bob"], "dark_mode": false}}`)},
+	}
+
+	env := map[string]string{"FLAG_BETA_API": "globex"}
+	meter := &testMeter{counts: make(map[string]int64)}
+
+	set, err := flags.NewSet(
+		[]*flags.Flag{newEditor, betaAPI, darkMode},
+		flags.SetOptions{Path: []string{"flags"}, Meter: meter},
+		config.WithPrefix("FLAG_"),
+		config.WithSources(
+			&config.FileSource{Path: "flags.json", FS: files},
+			&config.MapSource{Values: env},
+		),
+	)
+	require.NoError(t, err)
+
+	ctx := flags.NewContext(t.Context(), set)
+	ctx = flags.WithSubject(ctx, flags.SubjectUser, "alice")
+	ctx = flags.WithSubject(ctx, flags.SubjectTenant, "acme")
+
+	require.True(t, flags.Enabled(ctx, newEditor))
+	require.False(t, flags.Enabled(ctx, betaAPI))
+	require.False(t, flags.Enabled(ctx, darkMode))
+	require.True(t, flags.Enabled(flags.WithSubject(ctx, flags.SubjectTenant, "globex"), betaAPI))
+	require.False(t, flags.Enabled(flags.WithSubject(ctx, flags.SubjectUser, "carol"), newEditor))
+
+	require.Equal(t, map[string]int64{
+		"new_editor=true":  1,
+		"new_editor=false": 1,
+		"beta-api=true":    1,
+		"beta-api=false":   1,
+		"dark_mode=false":  1,
+	}, meter.counts)
+
+	t.Run("Reload", func(t *testing.T) {
+		env["FLAG_BETA_API"] = "100%"
+
+		require.NoError(t, set.Reload())
+		require.True(t, set.Enabled(ctx, betaAPI))
+
+		env["FLAG_BETA_API"] = "200%"
+
+		require.ErrorIs(t, set.Reload(), config.ErrOutOfRange)
+		require.Equal(t, flags.Rule{Percentage: 100}, set.Rule(betaAPI))
+	})
+
+	t.Run("NoSet", func(t *testing.T) {
+		t.Parallel()
+
+		noSetCtx := flags.WithSubject(t.Context(), flags.SubjectTenant, "acme")
+
+		require.True(t, flags.Enabled(noSetCtx, betaAPI))
+		require.False(t, flags.Enabled(noSetCtx, newEditor))
+		require.True(t, flags.Enabled(noSetCtx, darkMode))
+	})
+}
+
+func TestNewSetErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := flags.NewSet(
+		[]*flags.Flag{{Name: "flag"}, {Name: "flag"}},
+		flags.SetOptions{},
+		config.WithSources(&config.MapSource{}),
+	)
+	require.ErrorIs(t, err, flags.ErrDuplicateFlag)
+
+	_, err = flags.NewSet([]*flags.Flag{{Name: ""}}, flags.SetOptions{}, config.WithSources(&config.MapSource{}))
+	require.ErrorIs(t, err, flags.ErrInvalidName)
+
+	_, err = flags.NewSet(
+		[]*flags.Flag{{Name: "flag"}},
+		flags.SetOptions{},
+		config.WithSources(&config.MapSource{Values: map[string]string{"FLAG": "-5%"}}),
+	)
+	require.ErrorIs(t, err, config.ErrOutOfRange)
+}
@@ -0,0 +1,126 @@
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+// Rule decides whether a flag is enabled for a subject.
+//
+// Rules are parsed from a comma-separated list of tokens, so they can be set from a single variable:
+//   - "true" enables the flag for everyone, "false" only for the subjects selected by other tokens.
+//   - A percentage, e.g. "25%", enables the flag for a stable share of subjects.
+//   - Any other token is a subject ID, that is always allowed.
+//
+// For example, NEW_EDITOR="10%,alice,bob" enables the flag for alice, bob, and 10% of the other users.
+type Rule struct {
+	// Enabled turns the flag on for everyone.
+	Enabled bool
+	// Percentage of subjects the flag is enabled for, from 0 to 100.
+	Percentage float64
+	// Allow lists the IDs of the subjects the flag is always enabled for.
+	Allow []string
+}
+
+// RuleParser is a parsing function for config.LoadEnv, that returns the Rule representation of the variable.
+func RuleParser(value string) (Rule, error) {
+	tokens, err := config.SliceParser(config.StringParser)(value)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	var rule Rule
+
+	for _, token := range tokens {
+		switch {
+		case strings.EqualFold(token, "true"):
+			rule.Enabled = true
+		case strings.EqualFold(token, "false"):
+		case strings.HasSuffix(token, "%"):
+			percentage, err := strconv.ParseFloat(strings.TrimSuffix(token, "%"), 64)
+			if err != nil {
+				return Rule{}, fmt.Errorf(`invalid percentage "%s": %w`, token, err)
+			}
+
+			if percentage < 0 || percentage > 100 {
+				return Rule{}, fmt.Errorf(`%w: percentage "%s" is not between 0%% and 100%%`, config.ErrOutOfRange, token)
+			}
+
+			rule.Percentage = percentage
+		default:
+			rule.Allow = append(rule.Allow, token)
+		}
+	}
+
+	return rule, nil
+}
+
+// String formats the rule in the format accepted by RuleParser.
+func (rule Rule) String() string {
+	var tokens []string
+
+	if rule.Enabled {
+		tokens = append(tokens, "true")
+	}
+
+	if rule.Percentage > 0 {
+		tokens = append(tokens, strconv.FormatFloat(rule.Percentage, 'f', -1, 64)+"%")
+	}
+
+	tokens = append(tokens, rule.Allow...)
+
+	if len(tokens) == 0 {
+		return "false"
+	}
+
+	return strings.Join(tokens, ",")
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (rule Rule) MarshalText() ([]byte, error) {
+	return []byte(rule.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (rule *Rule) UnmarshalText(text []byte) error {
+	parsed, err := RuleParser(string(text))
+	if err != nil {
+		return err
+	}
+
+	*rule = parsed
+
+	return nil
+}
+
+// Match returns true if the rule enables the flag with the given name, for the subject with the given ID. The ID
+// is empty if the subject is unknown, in which case only rules enabled for everyone match.
+func (rule Rule) Match(name, id string) bool {
+	if rule.Enabled {
+		return true
+	}
+
+	if id == "" {
+		return false
+	}
+
+	if slices.Contains(rule.Allow, id) {
+		return true
+	}
+
+	return rule.Percentage > 0 && bucket(name, id) < rule.Percentage
+}
+
+// bucket assigns a subject to a stable value between 0 and 100, that differs between flags so the same subjects
+// are not always the first to get new features.
+func bucket(name, id string) float64 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name + ":" + id))
+
+	return float64(hash.Sum32()%10000) / 100
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// VarError describes a variable that could not be loaded.
+type VarError struct {
+	// Name of the variable.
+	Name string
+	// Raw value of the variable. Empty if the variable is missing. Secrets are redacted, and values read from a
+	// reference are replaced with the reference itself.
+	Value string
+	// Err is the error returned by the parser, or ErrRequiredVariable.
+	Err error
+}
+
+func (err *VarError) Error() string {
+	if err.Value == "" {
+		return fmt.Sprintf("%s: %v", err.Name, err.Err)
+	}
+
+	return fmt.Sprintf("%s=%q: %v", err.Name, err.Value, err.Err)
+}
+
+func (err *VarError) Unwrap() error {
+	return err.Err
+}
+
+// VarErrors extracts every VarError from an error, including errors joined with errors.Join.
+func VarErrors(err error) []*VarError {
+	//nolint:errorlint // The error tree is walked manually.
+	switch typed := err.(type) {
+	case *VarError:
+		return []*VarError{typed}
+	case interface{ Unwrap() []error }:
+		var output []*VarError
+		for _, child := range typed.Unwrap() {
+			output = append(output, VarErrors(child)...)
+		}
+
+		return output
+	case interface{ Unwrap() error }:
+		return VarErrors(typed.Unwrap())
+	default:
+		return nil
+	}
+}
+
+// Loader reads variables like LoadEnv, but records every parsing failure and missing required variable instead of
+// silently returning the fallback. Once every variable is loaded, Loader.Err returns all the errors at once.
+//
+//	loader := config.NewLoader()
+//
+//	port := config.LoadVar(loader, "PORT", 8080, config.IntParser)
+//	dsn := config.RequireVar(loader, "DSN", config.StringParser)
+//
+//	if err := loader.Err(); err != nil {
+//		return err
+//	}
+//
+// A Loader is safe for concurrent use.
+type Loader struct {
+	options *structOptions
+
+	errs []error
+	mu   sync.Mutex
+}
+
+// NewLoader creates a new Loader. Options are shared with LoadStruct.
+func NewLoader(options ...StructOption) *Loader {
+	loader := &Loader{options: newStructOptions(options)}
+
+	for _, source := range loader.options.sources {
+		err := source.Load()
+		if err != nil {
+			loader.errs = append(loader.errs, fmt.Errorf("load source %s: %w", source.Name(), err))
+		}
+	}
+
+	return loader
+}
+
+// Err returns every error recorded by the loader, joined using errors.Join. Each variable error is a *VarError.
+func (loader *Loader) Err() error {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	return errors.Join(loader.errs...)
+}
+
+func (loader *Loader) report(name, value string, err error) {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	loader.errs = append(loader.errs, &VarError{Name: name, Value: value, Err: err})
+}
+
+// rawValue is a value read from a source.
+type rawValue struct {
+	value  string
+	source string
+	// Reference the value was read from, if any.
+	ref string
+}
+
+// display returns a representation of the value that is safe to print.
+func (value rawValue) display(secret bool) string {
+	switch {
+	case value.ref != "":
+		return value.ref
+	case secret:
+		return Redacted
+	default:
+		return value.value
+	}
+}
+
+// parseError returns the error of a value that failed to parse. Parsers and validators may include the value in
+// their message, so the errors of secrets, and of values read from a reference, are replaced with a generic one.
+func (value rawValue) parseError(secret bool, err error) error {
+	if !secret && value.ref == "" {
+		return err
+	}
+
+	// Sentinels do not contain the value, so they are kept for errors.Is.
+	for _, sentinel := range []error{ErrOutOfRange, ErrPatternMismatch, ErrEmptyValue} {
+		if errors.Is(err, sentinel) {
+			return fmt.Errorf("invalid value: %w", sentinel)
+		}
+	}
+
+	return fmt.Errorf("invalid value: %w", ErrInvalidFormat)
+}
+
+// resolve looks for a key in every source, starting with the one that has the highest precedence. If the key is not
+// set in a source, its variant with the SecretFileSuffix is looked up as well. References are only resolved for
+// secrets, so other values that start with file:// or env:// are kept as-is.
+func (loader *Loader) resolve(key Key, secret bool) (rawValue, bool, error) {
+	for _, source := range slices.Backward(loader.options.sources) {
+		dereference := secret
+
+		value, ok := source.Lookup(key)
+		if !ok && key.Env != "" {
+			if path, found := source.Lookup(Key{Env: key.Env + SecretFileSuffix}); found {
+				value, ok, dereference = FileReference+path, true, true
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		output := rawValue{value: value, source: source.Name()}
+		if !dereference {
+			return output, true, nil
+		}
+
+		resolved, isRef, err := resolveReference(value, loader.options.lookup)
+		if isRef {
+			output.value, output.ref = resolved, value
+		}
+
+		return output, true, err
+	}
+
+	return rawValue{}, false, nil
+}
+
+// lookup returns the raw value of a key, and the prefixed name of its variable. Errors are recorded in the loader.
+func (loader *Loader) lookup(key Key) (string, rawValue, bool) {
+	key.Env = loader.options.prefix + key.Env
+
+	value, ok, err := loader.resolve(key, false)
+	if err != nil {
+		loader.report(key.Env, value.ref, err)
+
+		return key.Env, value, false
+	}
+
+	return key.Env, value, ok
+}
+
+// LoadVar loads a variable using the provided parser. If the variable is not set, the fallback value is returned.
+// If parsing fails, the error is recorded in the loader, and the fallback is returned.
+//
+// Values can be read from a file by setting the variable with the SecretFileSuffix. Use SecretParser to resolve
+// file:// and env:// references.
+func LoadVar[T any](loader *Loader, name string, fallback T, parser func(string) (T, error)) T {
+	return LoadKey(loader, Key{Env: name}, fallback, parser)
+}
+
+// LoadKey works like LoadVar, but looks the value up with a complete Key, so structured sources such as files can
+// provide it. The prefix of the loader is prepended to the name of the variable.
+func LoadKey[T any](loader *Loader, key Key, fallback T, parser func(string) (T, error)) T {
+	name, value, ok := loader.lookup(key)
+	if !ok {
+		return fallback
+	}
+
+	parsedValue, err := parser(value.value)
+	if err != nil {
+		loader.report(name, value.display(false), value.parseError(false, err))
+
+		return fallback
+	}
+
+	return parsedValue
+}
+
+// RequireVar works like LoadVar, but records an ErrRequiredVariable error if the variable is not set.
+func RequireVar[T any](loader *Loader, name string, parser func(string) (T, error)) T {
+	var zero T
+
+	name, value, ok := loader.lookup(Key{Env: name})
+	if !ok {
+		loader.report(name, "", ErrRequiredVariable)
+
+		return zero
+	}
+
+	parsedValue, err := parser(value.value)
+	if err != nil {
+		loader.report(name, value.display(false), value.parseError(false, err))
+
+		return zero
+	}
+
+	return parsedValue
+}
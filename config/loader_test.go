@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		loader := config.NewLoader(config.WithLookup(mapLookup(map[string]string{
+			"PORT": "3000",
+			"DSN":  "postgres://localhost",
+		})))
+
+		require.Equal(t, 3000, config.LoadVar(loader, "PORT", 8080, config.IntParser))
+		require.Equal(t, "info", config.LoadVar(loader, "LEVEL", "info", config.StringParser))
+		require.Equal(t, "postgres://localhost", config.RequireVar(loader, "DSN", config.StringParser))
+		require.NoError(t, loader.Err())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		loader := config.NewLoader(config.WithPrefix("APP_"), config.WithLookup(mapLookup(map[string]string{
+			"APP_PORT":  "80a0",
+			"APP_LEVEL": "trace",
+		})))
+
+		require.Equal(t, 8080, config.LoadVar(loader, "PORT", 8080, config.IntParser))
+		require.Equal(t, "info", config.LoadVar(
+			loader, "LEVEL", "info", config.EnumParser(config.StringParser, "debug", "info"),
+		))
+		require.Empty(t, config.RequireVar(loader, "DSN", config.StringParser))
+
+		err := loader.Err()
+		require.ErrorIs(t, err, config.ErrRequiredVariable)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+
+		varErrs := config.VarErrors(err)
+		require.Len(t, varErrs, 3)
+		require.Equal(t, "APP_PORT", varErrs[0].Name)
+		require.Equal(t, "80a0", varErrs[0].Value)
+		require.Equal(t, "APP_LEVEL", varErrs[1].Name)
+		require.Equal(t, "trace", varErrs[1].Value)
+		require.Equal(t, &config.VarError{Name: "APP_DSN", Err: config.ErrRequiredVariable}, varErrs[2])
+
+		require.Equal(t, `APP_PORT="80a0": strconv.Atoi: parsing "80a0": invalid syntax`, varErrs[0].Error())
+		require.Equal(t, "APP_DSN: required variable is not set", varErrs[2].Error())
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		t.Parallel()
+
+		loader := config.NewLoader(config.WithPrefix("APP_"), config.WithSources(
+			&config.FileSource{Path: "config.json", FS: fstest.MapFS{
+				"config.json": {Data: []byte(`{"server": {"port": 3000, "host": "0.0.0.0"}}`)},
+			}},
+			&config.MapSource{Values: map[string]string{"APP_HOST": "localhost"}},
+		))
+
+		port := config.LoadKey(loader, config.Key{Env: "PORT", Path: []string{"server", "port"}}, 8080, config.IntParser)
+		host := config.LoadKey(loader, config.Key{Env: "HOST", Path: []string{"server", "host"}}, "", config.StringParser)
+
+		require.Equal(t, 3000, port)
+		require.Equal(t, "localhost", host)
+		require.NoError(t, loader.Err())
+	})
+}
+
+func TestVarErrors(t *testing.T) {
+	t.Parallel()
+
+	varErr := &config.VarError{Name: "FOO", Err: config.ErrRequiredVariable}
+
+	require.Nil(t, config.VarErrors(nil))
+	require.Nil(t, config.VarErrors(errPanic))
+	require.Equal(t, []*config.VarError{varErr}, config.VarErrors(varErr))
+	require.Equal(t, []*config.VarError{varErr}, config.VarErrors(errors.Join(errPanic, varErr)))
+}
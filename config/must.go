@@ -1,5 +1,11 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
 // Must automatically panics if the error is not nil.
 func Must[T any](value T, err error) T {
 	if err != nil {
@@ -20,3 +26,84 @@ func MustUnmarshal[T any](unmarshal func([]byte, any) error, src []byte) T {
 
 	return value
 }
+
+// PanicError is the value of the panics raised by MustLabel and MustAll. It records where the panic was raised, so
+// the failing configuration can be found from the logs.
+type PanicError struct {
+	// Label describes the value that failed to load, e.g. "postgres config". May be empty.
+	Label string
+	// File and Line of the call to MustLabel or MustAll.
+	File string
+	Line int
+	// Err is the error that caused the panic.
+	Err error
+}
+
+func newPanicError(label string, err error) *PanicError {
+	// Skip newPanicError and the Must function that called it.
+	_, file, line, _ := runtime.Caller(2)
+
+	return &PanicError{Label: label, File: file, Line: line, Err: err}
+}
+
+func (err *PanicError) Error() string {
+	location := fmt.Sprintf("%s:%d", err.File, err.Line)
+	if err.Label != "" {
+		location = err.Label + " (" + location + ")"
+	}
+
+	return "load " + location + ": " + err.Err.Error()
+}
+
+func (err *PanicError) Unwrap() error {
+	return err.Err
+}
+
+// MustLabel works like Must, but panics with a *PanicError that carries the label and the call site.
+//
+//	var Postgres = config.MustLabel("postgres config", loadPostgres())
+func MustLabel[T any](label string, value T, err error) T {
+	if err != nil {
+		panic(newPanicError(label, err))
+	}
+
+	return value
+}
+
+// MustAll runs every check, and panics once with a *PanicError that joins all their errors. Checks are usually
+// Loader.Err methods, or functions that load a struct.
+//
+//	config.MustAll(loader.Err, func() error { return config.LoadStruct(&cfg) })
+func MustAll(checks ...func() error) {
+	errs := make([]error, 0, len(checks))
+	for _, check := range checks {
+		errs = append(errs, check())
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		panic(newPanicError("", err))
+	}
+}
+
+// Recover runs fn, and returns the error it panicked with, if any. Panics with values that are not errors are
+// propagated.
+func Recover(fn func()) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		recoveredErr, ok := recovered.(error)
+		if !ok {
+			panic(recovered)
+		}
+
+		err = recoveredErr
+	}()
+
+	fn()
+
+	return nil
+}
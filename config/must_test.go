@@ -2,7 +2,10 @@ package config_test
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -49,3 +52,57 @@ func TestMustUnmarshal(t *testing.T) {
 		config.MustUnmarshal[string](unmarshalFunc, invalidData)
 	})
 }
+
+func TestMustLabel(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "foo", config.MustLabel("foo config", "foo", nil))
+
+	err := config.Recover(func() {
+		config.MustLabel("foo config", "", errPanic)
+	})
+
+	var panicErr *config.PanicError
+
+	require.ErrorAs(t, err, &panicErr)
+	require.ErrorIs(t, err, errPanic)
+	require.Equal(t, "foo config", panicErr.Label)
+	require.Equal(t, "must_test.go", filepath.Base(panicErr.File))
+	require.Equal(t, fmt.Sprintf("load foo config (%s:%d): panic", panicErr.File, panicErr.Line), err.Error())
+}
+
+func TestMustAll(t *testing.T) {
+	t.Parallel()
+
+	loader := config.NewLoader(config.WithLookup(mapLookup(map[string]string{"PORT": "invalid"})))
+	config.LoadVar(loader, "PORT", 8080, config.IntParser)
+	config.RequireVar(loader, "DSN", config.StringParser)
+
+	var cfg struct {
+		Timeout time.Duration `env:"TIMEOUT"`
+	}
+
+	loadStruct := func() error {
+		return config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{"TIMEOUT": "never"})))
+	}
+
+	require.NotPanics(t, func() {
+		config.MustAll(func() error { return nil })
+	})
+
+	config.RequireMustPanics(t, func() {
+		config.MustAll(loader.Err, loadStruct)
+	}, "PORT", "DSN", "TIMEOUT")
+
+	config.RequireLoaderErrors(t, loader, "DSN", "PORT")
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, config.Recover(func() {}))
+	require.ErrorIs(t, config.Recover(func() { config.Must("", errPanic) }), errPanic)
+	require.PanicsWithValue(t, "not an error", func() {
+		_ = config.Recover(func() { panic("not an error") })
+	})
+}
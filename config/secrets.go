@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// SecretFileSuffix is appended to the name of a variable to read its value from a file, e.g. DB_PASSWORD_FILE
+	// for DB_PASSWORD. This is the convention used by Docker and Kubernetes secret mounts.
+	SecretFileSuffix = "_FILE"
+	// FileReference prefixes secrets that must be read from a file, e.g. "file:///run/secrets/db_password".
+	FileReference = "file://"
+	// EnvReference prefixes secrets that must be read from another variable, e.g. "env://DB_PASSWORD".
+	EnvReference = "env://"
+
+	// Redacted replaces the value of secrets in reports.
+	Redacted = "[REDACTED]"
+)
+
+var ErrUnresolvedReference = errors.New("unresolved reference")
+
+// ResolveReference returns the value pointed by a file:// or env:// reference. Other values are returned as-is.
+// Trailing newlines are trimmed from files.
+func ResolveReference(value string) (string, error) {
+	resolved, _, err := resolveReference(value, os.LookupEnv)
+
+	return resolved, err
+}
+
+// SecretParser is a parsing function for LoadEnv, that resolves file:// and env:// references before passing the
+// value to the sub-parser.
+func SecretParser[T any](parser func(string) (T, error)) func(string) (T, error) {
+	return func(value string) (T, error) {
+		resolved, err := ResolveReference(value)
+		if err != nil {
+			var zero T
+
+			return zero, err
+		}
+
+		return parser(resolved)
+	}
+}
+
+// resolveReference resolves a reference, and returns whether the value was a reference.
+func resolveReference(value string, lookup func(string) (string, bool)) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(value, FileReference):
+		content, err := os.ReadFile(strings.TrimPrefix(value, FileReference))
+		if err != nil {
+			return "", true, fmt.Errorf("%w: %w", ErrUnresolvedReference, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), true, nil
+	case strings.HasPrefix(value, EnvReference):
+		name := strings.TrimPrefix(value, EnvReference)
+
+		resolved, ok := lookup(name)
+		if !ok {
+			return "", true, fmt.Errorf("%w: variable %s is not set", ErrUnresolvedReference, name)
+		}
+
+		return resolved, true, nil
+	default:
+		return value, false, nil
+	}
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceDefault is the source reported for fields that use the value of their default tag.
+const SourceDefault = "default"
+
+var (
+	_ Source          = (*EnvSource)(nil)
+	_ WatchableSource = (*FileSource)(nil)
+	_ Source          = (*MapSource)(nil)
+)
+
+// Key identifies a configuration field across sources.
+type Key struct {
+	// Env is the name of the variable, including prefixes.
+	Env string
+	// Path of the field in structured sources, such as files. It is built from the yaml or json tags of the
+	// field and its parents. Empty for values loaded through a Loader.
+	Path []string
+	// Sep is the separator used to split slice and map values.
+	Sep string
+	// KVSep is the separator used between keys and values of maps.
+	KVSep string
+	// Encoding of the value, as set by TagEncoding.
+	Encoding string
+	// Type of the field. Nil for values loaded through a Loader.
+	Type reflect.Type
+}
+
+// Source provides raw values for configuration fields.
+type Source interface {
+	// Name identifies the source in reports.
+	Name() string
+	// Load is called before each resolution, so the source can refresh its content.
+	Load() error
+	// Lookup returns the raw value of a field, and whether it is set.
+	Lookup(key Key) (string, bool)
+}
+
+// WatchableSource is implemented by sources that can detect changes in their content, such as files.
+type WatchableSource interface {
+	Source
+	// Changed reports whether the content of the source changed since the last call to Load.
+	Changed() (bool, error)
+}
+
+// EnvSource reads values from environment variables. Empty variables are treated as unset.
+type EnvSource struct {
+	// LookupEnv replaces the function used to read variables. It defaults to os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+}
+
+func (source *EnvSource) Name() string {
+	return "env"
+}
+
+func (source *EnvSource) Load() error {
+	return nil
+}
+
+func (source *EnvSource) Lookup(key Key) (string, bool) {
+	lookup := source.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	value, ok := lookup(key.Env)
+
+	return value, ok && value != ""
+}
+
+// MapSource reads values from a static map, indexed by variable name.
+type MapSource struct {
+	// SourceName is returned by Name. It defaults to "map".
+	SourceName string
+	Values     map[string]string
+}
+
+func (source *MapSource) Name() string {
+	if source.SourceName == "" {
+		return "map"
+	}
+
+	return source.SourceName
+}
+
+func (source *MapSource) Load() error {
+	return nil
+}
+
+func (source *MapSource) Lookup(key Key) (string, bool) {
+	value, ok := source.Values[key.Env]
+
+	return value, ok
+}
+
+// FileSource reads values from a structured file, such as YAML or JSON. Fields are matched using their
+// Key.Path, built from their yaml or json tags.
+//
+//	source := &config.FileSource{Path: "config.yaml", Unmarshal: yaml.Unmarshal}
+//
+// String values in the file can reference environment variables using the ${VAR} syntax. Missing variables are
+// replaced with an empty string.
+//
+// Lists are joined using the separator of the field, so they go through the same parser as environment variables.
+// Objects, and lists for fields of type []any, are encoded as JSON.
+type FileSource struct {
+	Path string
+	// Unmarshal decodes the content of the file. It defaults to json.Unmarshal.
+	Unmarshal func([]byte, any) error
+	// FS to read the file from. If nil, the file is read from the local filesystem.
+	FS fs.FS
+	// Optional files are ignored if they do not exist.
+	Optional bool
+	// LookupEnv is used to interpolate ${VAR} references. It defaults to os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+
+	values   map[string]any
+	checksum [sha256.Size]byte
+	mu       sync.RWMutex
+}
+
+func (source *FileSource) Name() string {
+	return "file:" + source.Path
+}
+
+func (source *FileSource) Load() error {
+	content, err := source.read()
+	if err != nil {
+		return err
+	}
+
+	if content == nil {
+		source.setValues(nil, sha256.Sum256(nil))
+
+		return nil
+	}
+
+	unmarshal := source.Unmarshal
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	var values map[string]any
+
+	err = unmarshal(content, &values)
+	if err != nil {
+		return fmt.Errorf("unmarshal file: %w", err)
+	}
+
+	lookup := source.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	interpolated, _ := interpolate(values, lookup).(map[string]any)
+	source.setValues(interpolated, sha256.Sum256(content))
+
+	return nil
+}
+
+// Changed reports whether the content of the file changed since the last call to Load.
+func (source *FileSource) Changed() (bool, error) {
+	content, err := source.read()
+	if err != nil {
+		return false, err
+	}
+
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	return sha256.Sum256(content) != source.checksum, nil
+}
+
+func (source *FileSource) Lookup(key Key) (string, bool) {
+	if len(key.Path) == 0 {
+		return "", false
+	}
+
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	var current any = source.values
+
+	for _, name := range key.Path {
+		current = mapValue(current, name)
+		if current == nil {
+			return "", false
+		}
+	}
+
+	return stringify(current, key)
+}
+
+// read returns the content of the file. Missing optional files return nil content.
+func (source *FileSource) read() ([]byte, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	if source.FS != nil {
+		content, err = fs.ReadFile(source.FS, source.Path)
+	} else {
+		content, err = os.ReadFile(source.Path)
+	}
+
+	if errors.Is(err, fs.ErrNotExist) && source.Optional {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	return content, nil
+}
+
+func (source *FileSource) setValues(values map[string]any, checksum [sha256.Size]byte) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	source.values = values
+	source.checksum = checksum
+}
+
+var interpolationRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+// interpolate replaces ${VAR} references in every string of a decoded file.
+func interpolate(value any, lookup func(string) (string, bool)) any {
+	switch typed := value.(type) {
+	case string:
+		return interpolationRegexp.ReplaceAllStringFunc(typed, func(match string) string {
+			variable, _ := lookup(interpolationRegexp.FindStringSubmatch(match)[1])
+
+			return variable
+		})
+	case map[string]any:
+		output := make(map[string]any, len(typed))
+		for key, child := range typed {
+			output[key] = interpolate(child, lookup)
+		}
+
+		return output
+	case map[any]any:
+		output := make(map[string]any, len(typed))
+		for key, child := range typed {
+			output[fmt.Sprint(key)] = interpolate(child, lookup)
+		}
+
+		return output
+	case []any:
+		output := make([]any, len(typed))
+		for i, child := range typed {
+			output[i] = interpolate(child, lookup)
+		}
+
+		return output
+	default:
+		return value
+	}
+}
+
+// mapValue returns the value of a key in a decoded object. Keys are matched exactly first, then case-insensitively.
+func mapValue(value any, name string) any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	if child, ok := object[name]; ok {
+		return child
+	}
+
+	for key, child := range object {
+		if strings.EqualFold(key, name) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// stringify converts a decoded value back to the raw format expected by the parser of a field.
+func stringify(value any, key Key) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case time.Time:
+		return typed.Format(time.RFC3339Nano), true
+	case float64:
+		// Prevent large numbers from being formatted with an exponent.
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case map[string]any:
+		if key.Type == nil || key.Type.Kind() != reflect.Map || key.Encoding == "json" ||
+			key.Type == reflect.TypeFor[map[string]any]() {
+			return jsonString(typed)
+		}
+
+		sep, kvSep := separator(key.Sep), kvSeparator(key.KVSep)
+		pairs := make([]string, 0, len(typed))
+
+		for _, name := range slices.Sorted(maps.Keys(typed)) {
+			part, ok := stringify(typed[name], Key{})
+			if !ok {
+				return "", false
+			}
+
+			pairs = append(pairs, quote(name, sep, kvSep)+kvSep+quote(part, sep, kvSep))
+		}
+
+		return strings.Join(pairs, sep), true
+	case []any:
+		if (key.Type != nil && key.Type.Kind() != reflect.Slice) || key.Encoding == "json" ||
+			key.Type == reflect.TypeFor[[]any]() {
+			return jsonString(typed)
+		}
+
+		sep := separator(key.Sep)
+		parts := make([]string, 0, len(typed))
+
+		for _, elem := range typed {
+			// Slice parsers split on the separator, so elements that contain it cannot be represented, and the list
+			// is treated as unset.
+			part, ok := stringify(elem, Key{})
+			if !ok || strings.Contains(part, sep) {
+				return "", false
+			}
+
+			parts = append(parts, part)
+		}
+
+		return strings.Join(parts, sep), true
+	default:
+		return fmt.Sprint(typed), true
+	}
+}
+
+func jsonString(value any) (string, bool) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+
+	return string(encoded), true
+}
@@ -0,0 +1,145 @@
+package config_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+type sourcesTestConfig struct {
+	Port    int            `default:"8080"    env:"PORT"     json:"port"`
+	Host    string         `default:"0.0.0.0" env:"HOST"     json:"host"`
+	Timeout time.Duration  `env:"TIMEOUT"     json:"timeout"`
+	Origins []string       `env:"ORIGINS"     json:"origins" sep:";"`
+	Labels  map[string]any `env:"LABELS"      json:"labels"`
+	SMTP    struct {
+		Addr     string `env:"ADDR"     json:"addr"`
+		Password string `env:"PASSWORD" json:"password"`
+	} `json:"smtp" prefix:"SMTP_"`
+}
+
+func TestResolveSources(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"base.json": {Data: []byte(`{
+			"port": 3000,
+			"timeout": "10s",
+			"origins": ["a.com", "b.com"],
+			"labels": {"team": "core"},
+			"smtp": {"addr": "smtp.local:25", "password": "${SMTP_SECRET}"}
+		}`)},
+		"production.json": {Data: []byte(`{
+			"port": 1000000,
+			"smtp": {"addr": "smtp.example.com:587"}
+		}`)},
+		"typed.json": {Data: []byte(`{
+			"limits": {"/login": 5, "/signup": 1},
+			"messages": {"motd": "hello, world", "quote": "say \"hi\""},
+			"retry": {"attempts": 3, "backoff": "1s"}
+		}`)},
+		"separator.json": {Data: []byte(`{"origins": ["a.com;b.com", "c.com"]}`)},
+	}
+
+	env := map[string]string{"SMTP_SECRET": "secret", "TIMEOUT": "1m"}
+
+	t.Run("Layers", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg sourcesTestConfig
+
+		fields, err := config.Resolve(&cfg, config.WithSources(
+			&config.FileSource{Path: "base.json", FS: files, LookupEnv: mapLookup(env)},
+			&config.FileSource{Path: "production.json", FS: files, LookupEnv: mapLookup(env)},
+			&config.FileSource{Path: "missing.json", FS: files, Optional: true},
+			&config.EnvSource{LookupEnv: mapLookup(env)},
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, 1000000, cfg.Port)
+		require.Equal(t, "0.0.0.0", cfg.Host)
+		require.Equal(t, time.Minute, cfg.Timeout)
+		require.Equal(t, []string{"a.com", "b.com"}, cfg.Origins)
+		require.Equal(t, map[string]any{"team": "core"}, cfg.Labels)
+		require.Equal(t, "smtp.example.com:587", cfg.SMTP.Addr)
+		require.Equal(t, "secret", cfg.SMTP.Password)
+
+		origins := make(map[string]string, len(fields))
+		for _, field := range fields {
+			origins[field.Path] = field.Source
+		}
+
+		require.Equal(t, map[string]string{
+			"Port":          "file:production.json",
+			"Host":          config.SourceDefault,
+			"Timeout":       "env",
+			"Origins":       "file:base.json",
+			"Labels":        "file:base.json",
+			"SMTP.Addr":     "file:production.json",
+			"SMTP.Password": "file:base.json",
+		}, origins)
+	})
+
+	t.Run("MapSource", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg sourcesTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithSources(
+			&config.FileSource{Path: "base.json", FS: files},
+			&config.MapSource{Values: map[string]string{"PORT": "4000", "SMTP_ADDR": "smtp.map:25"}},
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, 4000, cfg.Port)
+		require.Equal(t, "smtp.map:25", cfg.SMTP.Addr)
+	})
+
+	t.Run("TypedMaps", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			Limits   map[string]int    `env:"LIMITS"   json:"limits"`
+			Messages map[string]string `env:"MESSAGES" json:"messages"`
+			Retry    struct {
+				Attempts int    `json:"attempts"`
+				Backoff  string `json:"backoff"`
+			} `encoding:"json" env:"RETRY" json:"retry"`
+		}
+
+		err := config.LoadStruct(&cfg, config.WithSources(&config.FileSource{Path: "typed.json", FS: files}))
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]int{"/login": 5, "/signup": 1}, cfg.Limits)
+		require.Equal(t, map[string]string{"motd": "hello, world", "quote": `say "hi"`}, cfg.Messages)
+		require.Equal(t, 3, cfg.Retry.Attempts)
+		require.Equal(t, "1s", cfg.Retry.Backoff)
+	})
+
+	t.Run("SeparatorInList", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg sourcesTestConfig
+
+		// The list cannot be split back into its elements, so it is ignored instead of being parsed as 3 elements.
+		err := config.LoadStruct(&cfg, config.WithSources(
+			&config.MapSource{Values: map[string]string{"ORIGINS": "d.com"}},
+			&config.FileSource{Path: "separator.json", FS: files},
+		))
+		require.NoError(t, err)
+		require.Equal(t, []string{"d.com"}, cfg.Origins)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg sourcesTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithSources(&config.FileSource{Path: "missing.json", FS: files}))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,727 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags read by LoadStruct.
+const (
+	// TagEnv sets the name of the environment variable used to load a field. Use "-" to ignore a field.
+	TagEnv = "env"
+	// TagDefault sets the raw value used when the variable is not set. It goes through the same parser as the
+	// variable.
+	TagDefault = "default"
+	// TagRequired marks a variable as mandatory, when set to "true".
+	TagRequired = "required"
+	// TagSep sets the separator used to split slice values. Defaults to a comma.
+	TagSep = "sep"
+	// TagEnum restricts the accepted values to a comma-separated list.
+	TagEnum = "enum"
+	// TagPrefix is set on nested structs, and is prepended to the variables of every field in the struct.
+	TagPrefix = "prefix"
+	// TagSecret marks a field as sensitive when set to "true". Its value is redacted from reports and errors.
+	TagSecret = "secret"
+	// TagDesc describes a field in generated documentation, such as WriteEnvExample and WriteMarkdown.
+	TagDesc = "desc"
+	// TagKVSep sets the separator between keys and values of map fields. Defaults to "=".
+	TagKVSep = "kvsep"
+	// TagEncoding sets the encoding of a field. "json" unmarshals the value into the field, whatever its type.
+	// "base64" and "hex" decode []byte fields, that are otherwise parsed as a list of numbers.
+	TagEncoding = "encoding"
+	// TagLayout sets the layout of time.Time fields, as accepted by time.Parse, or "unix" for Unix timestamps.
+	// Defaults to time.RFC3339.
+	TagLayout = "layout"
+)
+
+var (
+	ErrInvalidTarget    = errors.New("target must be a non-nil pointer to a struct")
+	ErrUnsupportedType  = errors.New("unsupported field type")
+	ErrRequiredVariable = errors.New("required variable is not set")
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	bytesType           = reflect.TypeFor[[]byte]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// Parsers used for specific types, that take precedence over the parsers inferred from the kind of the type.
+var typeParsers = map[reflect.Type]func(string) (reflect.Value, error){
+	reflect.TypeFor[time.Duration]():  reflectParser(DurationParser),
+	reflect.TypeFor[time.Time]():      reflectParser(TimeParser),
+	reflect.TypeFor[map[string]any](): reflectParser(JSONMapParser),
+	reflect.TypeFor[[]any]():          reflectParser(JSONSliceParser),
+	reflect.TypeFor[*url.URL]():       reflectParser(URLParser),
+	reflect.TypeFor[*regexp.Regexp](): reflectParser(RegexpParser),
+}
+
+// Parsers selected by the value of TagEncoding, for []byte fields.
+var encodingParsers = map[string]func(string) (reflect.Value, error){
+	"base64": reflectParser(Base64Parser),
+	"hex":    reflectParser(HexParser),
+}
+
+// Parsers used for the base kinds.
+var kindParsers = map[reflect.Kind]func(string) (reflect.Value, error){
+	reflect.String:  reflectParser(StringParser),
+	reflect.Bool:    reflectParser(BoolParser),
+	reflect.Int:     reflectParser(IntParser),
+	reflect.Int8:    reflectParser(Int8Parser),
+	reflect.Int16:   reflectParser(Int16Parser),
+	reflect.Int32:   reflectParser(Int32Parser),
+	reflect.Int64:   reflectParser(Int64Parser),
+	reflect.Uint:    reflectParser(UintParser),
+	reflect.Uint8:   reflectParser(Uint8Parser),
+	reflect.Uint16:  reflectParser(Uint16Parser),
+	reflect.Uint32:  reflectParser(Uint32Parser),
+	reflect.Uint64:  reflectParser(Uint64Parser),
+	reflect.Float32: reflectParser(Float32Parser),
+	reflect.Float64: reflectParser(Float64Parser),
+}
+
+type structOptions struct {
+	prefix  string
+	lookup  func(string) (string, bool)
+	sources []Source
+}
+
+// StructOption customizes the behavior of LoadStruct and Loader.
+type StructOption func(options *structOptions)
+
+func newStructOptions(options []StructOption) *structOptions {
+	opts := &structOptions{lookup: os.LookupEnv}
+	for _, option := range options {
+		option(opts)
+	}
+
+	if len(opts.sources) == 0 {
+		opts.sources = []Source{&EnvSource{LookupEnv: opts.lookup}}
+	}
+
+	return opts
+}
+
+// WithPrefix prepends a prefix to the name of every variable loaded.
+func WithPrefix(prefix string) StructOption {
+	return func(options *structOptions) {
+		options.prefix = prefix
+	}
+}
+
+// WithLookup replaces the function used to read variables. It defaults to os.LookupEnv.
+//
+// This option is ignored if WithSources is used.
+func WithLookup(lookup func(string) (string, bool)) StructOption {
+	return func(options *structOptions) {
+		options.lookup = lookup
+	}
+}
+
+// WithSources sets the sources values are read from, ordered from the lowest to the highest precedence: when a
+// value is present in multiple sources, the last one wins. Default values set by struct tags always have the lowest
+// precedence.
+//
+// By default, values are only read from the environment.
+func WithSources(sources ...Source) StructOption {
+	return func(options *structOptions) {
+		options.sources = sources
+	}
+}
+
+// structField is a leaf field of a struct, that can be loaded from a single variable.
+type structField struct {
+	// Path of the field in the root struct, e.g. "Postgres.DSN".
+	path string
+	key  Key
+
+	def      string
+	hasDef   bool
+	required bool
+	secret   bool
+	nonEmpty bool
+	enum     []string
+	desc     string
+
+	value reflect.Value
+	parse func(string) (reflect.Value, error)
+	// section is the nil pointer to a nested struct the field belongs to, if any.
+	section *lazySection
+}
+
+// lazySection is a nil pointer to a nested struct, that is only allocated once one of its fields is set by a source.
+// A nil section can mean a disabled feature, so default values alone do not allocate it.
+type lazySection struct {
+	parent *lazySection
+	// target is the nil pointer field, and value the struct allocated for it.
+	target reflect.Value
+	value  reflect.Value
+}
+
+func (section *lazySection) allocate() {
+	if section == nil {
+		return
+	}
+
+	section.parent.allocate()
+
+	if section.target.IsNil() {
+		section.target.Set(section.value)
+	}
+}
+
+func (section *lazySection) allocated() bool {
+	return section == nil || !section.target.IsNil()
+}
+
+// LoadStruct loads the exported fields of a struct from the environment, using struct tags to configure each field.
+// Other sources, such as files, can be used with WithSources.
+//
+//	type Config struct {
+//		Port     int           `env:"PORT"    default:"8080"`
+//		DSN      string        `env:"DSN"     required:"true"`
+//		Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+//		Origins  []string      `env:"ORIGINS" sep:";"`
+//		Level    string        `env:"LEVEL"   enum:"debug,info,warn,error"`
+//		Postgres struct {
+//			Schema string `env:"SCHEMA"`
+//		} `prefix:"POSTGRES_"`
+//	}
+//
+// The parser used for each field is inferred from its type, using the parsers of this package. Types that implement
+// encoding.TextUnmarshaler are also supported. Nested structs without an env tag are loaded recursively. Nil pointers
+// to nested structs are only allocated if one of their variables is set, so they can represent optional sections.
+//
+// Fields whose variable is not set, and that have no default, keep their current value, so LoadStruct can be used
+// to override a preset.
+//
+// Values can be read from a file by setting the variable with the SecretFileSuffix (e.g. DSN_FILE for DSN). Fields
+// tagged with secret:"true" are redacted from reports and errors, and can also be read from files or other variables
+// using a file:// or env:// reference.
+//
+// Fields can be validated with the min, max, pattern, format and nonempty tags. Once every variable is loaded,
+// the Validate method of structs that implement Validator is called.
+//
+// Every invalid or missing variable is reported at once, as a joined list of *VarError. Use VarErrors to
+// inspect them.
+func LoadStruct(dst any, options ...StructOption) error {
+	_, err := Resolve(dst, options...)
+
+	return err
+}
+
+// Field describes how a field was resolved by Resolve.
+type Field struct {
+	// Path of the field in the root struct, e.g. "Postgres.DSN".
+	Path string
+	// Key used to look up the field in the sources.
+	Key Key
+	// Source is the name of the source that provided the value. It is set to SourceDefault if the default value
+	// was used, and is empty if the field was left untouched.
+	Source string
+	// Raw value, before parsing. For secrets, this is either Redacted, or the reference the value was read from.
+	Raw string
+	// Value is the formatted value of the field once loaded, including fields left untouched. Non-empty secrets
+	// are Redacted.
+	Value string
+	// Secret is true if the field is tagged as secret, or if its value was read from a reference.
+	Secret bool
+}
+
+// Resolve works like LoadStruct, and also returns the origin of each field.
+func Resolve(dst any, options ...StructOption) ([]*Field, error) {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidTarget
+	}
+
+	loader := NewLoader(options...)
+
+	var (
+		fields []*Field
+		// Checks of the fields of nil sections, that only apply if the section is allocated.
+		pending []func()
+	)
+
+	err := walkStruct(target.Elem(), walkScope{prefix: loader.options.prefix}, func(field *structField) error {
+		resolved := &Field{Path: field.path, Key: field.key, Secret: field.secret}
+		fields = append(fields, resolved)
+
+		loaded, missing := loadField(loader, field, resolved)
+
+		check := func() {
+			if !field.section.allocated() {
+				*resolved = Field{Path: field.path, Key: field.key, Secret: field.secret}
+
+				return
+			}
+
+			switch {
+			case missing:
+				loader.report(field.key.Env, "", ErrRequiredVariable)
+			case loaded && field.nonEmpty && field.value.Len() == 0:
+				loader.report(field.key.Env, resolved.Raw, ErrEmptyValue)
+			}
+
+			resolved.Value = formatValue(field.value, field.key)
+			if resolved.Secret && resolved.Value != "" {
+				resolved.Value = Redacted
+			}
+		}
+
+		if field.section != nil {
+			pending = append(pending, check)
+		} else {
+			check()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, check := range pending {
+		check()
+	}
+
+	err = loader.Err()
+	if err != nil {
+		return fields, err
+	}
+
+	return fields, errors.Join(validateStruct(target.Elem(), "")...)
+}
+
+// loadField resolves the value of a field, and sets it. It returns whether the field was loaded without error, and
+// whether it is required but not set. Values set by a source allocate the nil section of the field.
+func loadField(loader *Loader, field *structField, resolved *Field) (bool, bool) {
+	value, ok, err := loader.resolve(field.key, field.secret)
+	if ok {
+		field.section.allocate()
+	}
+
+	if err != nil {
+		loader.report(field.key.Env, value.ref, err)
+
+		return false, false
+	}
+
+	switch {
+	case ok:
+	case field.hasDef:
+		value = rawValue{value: field.def, source: SourceDefault}
+	case field.required:
+		return false, true
+	default:
+		return true, false
+	}
+
+	resolved.Source = value.source
+	resolved.Raw = value.display(field.secret)
+	resolved.Secret = field.secret || value.ref != ""
+
+	parsed, err := field.parse(value.value)
+	if err != nil {
+		loader.report(field.key.Env, resolved.Raw, value.parseError(field.secret, err))
+
+		return false, false
+	}
+
+	field.value.Set(parsed)
+
+	return true, false
+}
+
+// walkScope holds the information inherited by nested structs.
+type walkScope struct {
+	prefix  string
+	path    string
+	keys    []string
+	section *lazySection
+}
+
+// walkStruct calls fn on every leaf field of a struct, recursing into nested structs.
+func walkStruct(value reflect.Value, scope walkScope, fn func(field *structField) error) error {
+	valueType := value.Type()
+
+	for i := range valueType.NumField() {
+		fieldType := valueType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		fieldPath := scope.path + fieldType.Name
+		fieldKeys := append(slices.Clone(scope.keys), fieldKey(fieldType))
+
+		env, hasEnv := fieldType.Tag.Lookup(TagEnv)
+		if env == "-" {
+			continue
+		}
+
+		if !hasEnv {
+			if !isNestedStruct(fieldType.Type) {
+				continue
+			}
+
+			section := scope.section
+
+			if fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					section = &lazySection{
+						parent: section,
+						target: fieldValue,
+						value:  reflect.New(fieldType.Type.Elem()),
+					}
+					fieldValue = section.value
+				}
+
+				fieldValue = fieldValue.Elem()
+			}
+
+			err := walkStruct(fieldValue, walkScope{
+				prefix:  scope.prefix + fieldType.Tag.Get(TagPrefix),
+				path:    fieldPath + ".",
+				keys:    fieldKeys,
+				section: section,
+			}, fn)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		parser, err := parserFor(fieldType.Type, fieldType.Tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+
+		field := &structField{
+			path: fieldPath,
+			key: Key{
+				Env:      scope.prefix + env,
+				Path:     fieldKeys,
+				Sep:      fieldType.Tag.Get(TagSep),
+				KVSep:    fieldType.Tag.Get(TagKVSep),
+				Encoding: fieldType.Tag.Get(TagEncoding),
+				Type:     fieldType.Type,
+			},
+			value:   fieldValue,
+			parse:   parser,
+			section: scope.section,
+		}
+
+		field.def, field.hasDef = fieldType.Tag.Lookup(TagDefault)
+		field.enum = enumValues(fieldType.Tag)
+		field.desc = fieldType.Tag.Get(TagDesc)
+
+		field.required, err = boolTag(fieldType.Tag, TagRequired)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+
+		field.secret, err = boolTag(fieldType.Tag, TagSecret)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+
+		field.nonEmpty, err = boolTag(fieldType.Tag, TagNonEmpty)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+
+		if kind := fieldType.Type.Kind(); field.nonEmpty && kind != reflect.Slice && kind != reflect.Map {
+			return fmt.Errorf("field %s: %w: %s tag on %s", fieldPath, ErrUnsupportedType, TagNonEmpty, fieldType.Type)
+		}
+
+		err = fn(field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boolTag parses a boolean tag. Missing tags are false.
+func boolTag(tag reflect.StructTag, name string) (bool, error) {
+	raw, ok := tag.Lookup(name)
+	if !ok {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s tag: %w", name, err)
+	}
+
+	return value, nil
+}
+
+// fieldKey returns the name of a field in structured files. It uses the yaml tag, then the json tag, and falls back
+// to the name of the field.
+func fieldKey(field reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// isNestedStruct returns true if the type is a struct (or a pointer to a struct) that has no parser of its own.
+func isNestedStruct(valueType reflect.Type) bool {
+	if valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if valueType.Kind() != reflect.Struct {
+		return false
+	}
+
+	if _, ok := typeParsers[valueType]; ok {
+		return false
+	}
+
+	return !reflect.PointerTo(valueType).Implements(textUnmarshalerType)
+}
+
+// parserFor returns a parser for the given type, wrapped to work with reflection.
+func parserFor(valueType reflect.Type, tag reflect.StructTag) (func(string) (reflect.Value, error), error) {
+	if name := tag.Get(TagEncoding); name == "json" {
+		return scalarParser(jsonParser(valueType), valueType, tag)
+	}
+
+	if name, ok := tag.Lookup(TagEncoding); ok && valueType == bytesType {
+		parser, ok := encodingParsers[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid %s tag: unknown encoding %q", TagEncoding, name)
+		}
+
+		return scalarParser(parser, valueType, tag)
+	}
+
+	if layout, ok := tag.Lookup(TagLayout); ok && valueType == timeType {
+		return scalarParser(layoutParser(layout), valueType, tag)
+	}
+
+	if parser, ok := typeParsers[valueType]; ok {
+		return scalarParser(parser, valueType, tag)
+	}
+
+	if reflect.PointerTo(valueType).Implements(textUnmarshalerType) {
+		return scalarParser(textParser(valueType), valueType, tag)
+	}
+
+	switch valueType.Kind() {
+	case reflect.Pointer:
+		elemParser, err := parserFor(valueType.Elem(), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(value string) (reflect.Value, error) {
+			parsed, err := elemParser(value)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			ptr := reflect.New(valueType.Elem())
+			ptr.Elem().Set(parsed)
+
+			return ptr, nil
+		}, nil
+	case reflect.Slice:
+		elemParser, err := parserFor(valueType.Elem(), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		sliceParser := SliceParserSep(separator(tag.Get(TagSep)), elemParser)
+
+		return func(value string) (reflect.Value, error) {
+			parsed, err := sliceParser(value)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			slice := reflect.MakeSlice(valueType, 0, len(parsed))
+			for _, elem := range parsed {
+				slice = reflect.Append(slice, elem)
+			}
+
+			return slice, nil
+		}, nil
+	case reflect.Map:
+		keyParser, err := parserFor(valueType.Key(), "")
+		if err != nil {
+			return nil, err
+		}
+
+		elemParser, err := parserFor(valueType.Elem(), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		mapParser := MapParserSep(separator(tag.Get(TagSep)), kvSeparator(tag.Get(TagKVSep)), keyParser, elemParser)
+
+		return func(value string) (reflect.Value, error) {
+			parsed, err := mapParser(value)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			result := reflect.MakeMapWithSize(valueType, len(parsed))
+			for key, elem := range parsed {
+				result.SetMapIndex(key, elem)
+			}
+
+			return result, nil
+		}, nil
+	default:
+		parser, ok := kindParsers[valueType.Kind()]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, valueType)
+		}
+
+		return scalarParser(convertParser(parser, valueType), valueType, tag)
+	}
+}
+
+// layoutParser parses times with the layout set by TagLayout.
+func layoutParser(layout string) func(string) (reflect.Value, error) {
+	if layout == "unix" {
+		return reflectParser(UnixTimeParser)
+	}
+
+	return reflectParser(TimeLayoutParser(layout))
+}
+
+// scalarParser applies the enum and validation tags to the parser of a single value.
+func scalarParser(
+	parser func(string) (reflect.Value, error), valueType reflect.Type, tag reflect.StructTag,
+) (func(string) (reflect.Value, error), error) {
+	return withValidation(withEnum(parser, tag), valueType, tag)
+}
+
+// separator returns the separator used for slices, defaulting to a comma.
+func separator(sep string) string {
+	if sep == "" {
+		return ","
+	}
+
+	return sep
+}
+
+// kvSeparator returns the separator used between keys and values of maps, defaulting to "=".
+func kvSeparator(sep string) string {
+	if sep == "" {
+		return "="
+	}
+
+	return sep
+}
+
+// reflectParser wraps a parser from this package so it returns a reflect.Value.
+func reflectParser[T any](parser func(string) (T, error)) func(string) (reflect.Value, error) {
+	return func(value string) (reflect.Value, error) {
+		parsed, err := parser(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(parsed), nil
+	}
+}
+
+// convertParser converts the output of a parser to a named type, e.g. "type Level string".
+func convertParser(
+	parser func(string) (reflect.Value, error), valueType reflect.Type,
+) func(string) (reflect.Value, error) {
+	return func(value string) (reflect.Value, error) {
+		parsed, err := parser(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return parsed.Convert(valueType), nil
+	}
+}
+
+// jsonParser unmarshals JSON values into a new value of the given type.
+func jsonParser(valueType reflect.Type) func(string) (reflect.Value, error) {
+	return func(value string) (reflect.Value, error) {
+		ptr := reflect.New(valueType)
+
+		err := json.Unmarshal([]byte(value), ptr.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return ptr.Elem(), nil
+	}
+}
+
+// textParser parses values using the encoding.TextUnmarshaler implementation of the type.
+func textParser(valueType reflect.Type) func(string) (reflect.Value, error) {
+	return func(value string) (reflect.Value, error) {
+		ptr := reflect.New(valueType)
+
+		unmarshaler, _ := ptr.Interface().(encoding.TextUnmarshaler)
+
+		err := unmarshaler.UnmarshalText([]byte(value))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return ptr.Elem(), nil
+	}
+}
+
+// withEnum restricts the values accepted by a parser, if the enum tag is set.
+func withEnum(parser func(string) (reflect.Value, error), tag reflect.StructTag) func(string) (reflect.Value, error) {
+	allowed := enumValues(tag)
+	if allowed == nil {
+		return parser
+	}
+
+	enumParser := EnumParser(StringParser, allowed...)
+
+	return func(value string) (reflect.Value, error) {
+		_, err := enumParser(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return parser(value)
+	}
+}
+
+// enumValues returns the values allowed by the enum tag, or nil if the tag is not set.
+func enumValues(tag reflect.StructTag) []string {
+	enum, ok := tag.Lookup(TagEnum)
+	if !ok {
+		return nil
+	}
+
+	allowed := strings.Split(enum, ",")
+	for i, value := range allowed {
+		allowed[i] = strings.TrimSpace(value)
+	}
+
+	return allowed
+}
@@ -0,0 +1,285 @@
+package config_test
+
+import (
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+type structTestLevel string
+
+type structTestNested struct {
+	Schema string `default:"public" env:"SCHEMA"`
+}
+
+type structTestConfig struct {
+	Port     int              `default:"8080"     env:"PORT"`
+	DSN      string           `env:"DSN"          required:"true"`
+	Timeout  time.Duration    `default:"5s"       env:"TIMEOUT"`
+	Origins  []string         `env:"ORIGINS"      sep:";"`
+	Ratios   []float64        `env:"RATIOS"`
+	Level    structTestLevel  `enum:"debug,info"  env:"LEVEL"`
+	Debug    *bool            `env:"DEBUG"`
+	Date     time.Time        `env:"DATE"`
+	Extra    map[string]any   `env:"EXTRA"`
+	Ignored  string           `env:"-"`
+	Postgres structTestNested `prefix:"POSTGRES_"`
+}
+
+func mapLookup(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+
+		return value, ok
+	}
+}
+
+func TestLoadStruct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Values", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg structTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithPrefix("APP_"), config.WithLookup(mapLookup(map[string]string{
+			"APP_PORT":            "3000",
+			"APP_DSN":             "postgres://localhost",
+			"APP_TIMEOUT":         "1m",
+			"APP_ORIGINS":         "a.com;b.com",
+			"APP_RATIOS":          "0.5,1.5",
+			"APP_LEVEL":           "debug",
+			"APP_DEBUG":           "true",
+			"APP_DATE":            "2020-01-02T15:04:05Z",
+			"APP_EXTRA":           `{"key":"value"}`,
+			"APP_POSTGRES_SCHEMA": "custom",
+		})))
+		require.NoError(t, err)
+
+		debug := true
+
+		require.Equal(t, structTestConfig{
+			Port:     3000,
+			DSN:      "postgres://localhost",
+			Timeout:  time.Minute,
+			Origins:  []string{"a.com", "b.com"},
+			Ratios:   []float64{0.5, 1.5},
+			Level:    "debug",
+			Debug:    &debug,
+			Date:     time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC),
+			Extra:    map[string]any{"key": "value"},
+			Postgres: structTestNested{Schema: "custom"},
+		}, cfg)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := structTestConfig{Origins: []string{"preset.com"}}
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"DSN": "postgres://localhost",
+		})))
+		require.NoError(t, err)
+
+		require.Equal(t, structTestConfig{
+			Port:     8080,
+			DSN:      "postgres://localhost",
+			Timeout:  5 * time.Second,
+			Origins:  []string{"preset.com"},
+			Postgres: structTestNested{Schema: "public"},
+		}, cfg)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg structTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{})))
+		require.ErrorIs(t, err, config.ErrRequiredVariable)
+		require.Equal(t, []*config.VarError{
+			{Name: "DSN", Err: config.ErrRequiredVariable},
+		}, config.VarErrors(err))
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg structTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"DSN":   "postgres://localhost",
+			"LEVEL": "trace",
+		})))
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg structTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"PORT":    "80a0",
+			"TIMEOUT": "forever",
+		})))
+		require.Error(t, err)
+
+		varErrs := config.VarErrors(err)
+		require.Len(t, varErrs, 3)
+		require.Equal(t, "PORT", varErrs[0].Name)
+		require.Equal(t, "80a0", varErrs[0].Value)
+		require.Equal(t, "DSN", varErrs[1].Name)
+		require.ErrorIs(t, varErrs[1], config.ErrRequiredVariable)
+		require.Equal(t, "TIMEOUT", varErrs[2].Name)
+		require.Equal(t, "forever", varErrs[2].Value)
+	})
+
+	t.Run("NilSection", func(t *testing.T) {
+		t.Parallel()
+
+		type section struct {
+			Addr string `env:"ADDR"   required:"true"`
+			Port int    `default:"25" env:"PORT"`
+		}
+
+		type sectionConfig struct {
+			SMTP *section `prefix:"SMTP_"`
+		}
+
+		// Defaults alone do not enable the section, and its required variables are not checked.
+		var cfg sectionConfig
+
+		require.NoError(t, config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{}))))
+		require.Nil(t, cfg.SMTP)
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{"SMTP_PORT": "587"})))
+		require.Equal(t, []*config.VarError{
+			{Name: "SMTP_ADDR", Err: config.ErrRequiredVariable},
+		}, config.VarErrors(err))
+
+		cfg = sectionConfig{}
+
+		err = config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{"SMTP_ADDR": "smtp"})))
+		require.NoError(t, err)
+		require.Equal(t, &section{Addr: "smtp", Port: 25}, cfg.SMTP)
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg structTestConfig
+
+		require.ErrorIs(t, config.LoadStruct(cfg), config.ErrInvalidTarget)
+		require.ErrorIs(t, config.LoadStruct((*structTestConfig)(nil)), config.ErrInvalidTarget)
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			Channel chan int `env:"CHANNEL"`
+		}
+
+		require.ErrorIs(t, config.LoadStruct(&cfg), config.ErrUnsupportedType)
+	})
+
+	t.Run("Types", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			URL     *url.URL        `env:"URL"`
+			IP      netip.Addr      `env:"IP"`
+			Subnets []netip.Prefix  `env:"SUBNETS"`
+			Size    config.ByteSize `env:"SIZE"        min:"1KiB"`
+			Pattern *regexp.Regexp  `env:"PATTERN"`
+			Level   slog.Level      `env:"LEVEL"`
+			Key     []byte          `encoding:"base64" env:"KEY"`
+			Hash    []byte          `encoding:"hex"    env:"HASH"`
+			Date    time.Time       `env:"DATE"        layout:"2006-01-02"`
+			Expiry  time.Time       `env:"EXPIRY"      layout:"unix"`
+		}
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"URL":     "https://example.com",
+			"IP":      "10.0.0.1",
+			"SUBNETS": "10.0.0.0/8,192.168.0.0/16",
+			"SIZE":    "10MiB",
+			"PATTERN": "^[a-z]+$",
+			"LEVEL":   "debug",
+			"KEY":     "c2VjcmV0",
+			"HASH":    "cafe",
+			"DATE":    "2020-01-02",
+			"EXPIRY":  "1577977445",
+		})))
+		require.NoError(t, err)
+
+		require.Equal(t, "https://example.com", cfg.URL.String())
+		require.Equal(t, netip.MustParseAddr("10.0.0.1"), cfg.IP)
+		require.Equal(t, []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+		}, cfg.Subnets)
+		require.Equal(t, 10*config.MiB, cfg.Size)
+		require.True(t, cfg.Pattern.MatchString("abc"))
+		require.Equal(t, slog.LevelDebug, cfg.Level)
+		require.Equal(t, []byte("secret"), cfg.Key)
+		require.Equal(t, []byte{0xca, 0xfe}, cfg.Hash)
+		require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), cfg.Date)
+		require.Equal(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC), cfg.Expiry)
+	})
+
+	t.Run("Maps", func(t *testing.T) {
+		t.Parallel()
+
+		type rateLimit struct {
+			Burst int `json:"burst"`
+		}
+
+		var cfg struct {
+			Limits     map[string]int           `env:"LIMITS"`
+			Timeouts   map[string]time.Duration `env:"TIMEOUTS"  kvsep:":"         sep:";"`
+			RateLimits map[string]rateLimit     `encoding:"json" env:"RATE_LIMITS"`
+		}
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"LIMITS":      "/login=5, /signup=1",
+			"TIMEOUTS":    "read:1s;write:5s",
+			"RATE_LIMITS": `{"/login":{"burst":10}}`,
+		})))
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]int{"/login": 5, "/signup": 1}, cfg.Limits)
+		require.Equal(t, map[string]time.Duration{"read": time.Second, "write": 5 * time.Second}, cfg.Timeouts)
+		require.Equal(t, map[string]rateLimit{"/login": {Burst: 10}}, cfg.RateLimits)
+	})
+
+	t.Run("InvalidEncoding", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			Key []byte `encoding:"base32" env:"KEY"`
+		}
+
+		require.Error(t, config.LoadStruct(&cfg))
+	})
+}
+
+func TestLoadStructEnv(t *testing.T) {
+	t.Setenv("SMTP_ADDR", "smtp.example.com:587")
+
+	var cfg struct {
+		Addr string `env:"ADDR"`
+	}
+
+	require.NoError(t, config.LoadStruct(&cfg, config.WithPrefix("SMTP_")))
+	require.Equal(t, "smtp.example.com:587", cfg.Addr)
+}
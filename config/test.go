@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RequireVarErrors asserts that err contains a *VarError for each of the given variables, and no other.
+func RequireVarErrors(t *testing.T, err error, names ...string) {
+	t.Helper()
+
+	require.Error(t, err)
+
+	actual := make([]string, 0, len(names))
+	for _, varErr := range VarErrors(err) {
+		actual = append(actual, varErr.Name)
+	}
+
+	require.ElementsMatch(t, names, actual, "unexpected variable errors: %v", err)
+}
+
+// RequireLoaderErrors asserts that the loader recorded an error for each of the given variables, and no other.
+func RequireLoaderErrors(t *testing.T, loader *Loader, names ...string) {
+	t.Helper()
+
+	RequireVarErrors(t, loader.Err(), names...)
+}
+
+// RequireMustPanics asserts that fn panics with an error, e.g. from Must or MustAll, that contains a *VarError for
+// each of the given variables, and no other.
+func RequireMustPanics(t *testing.T, fn func(), names ...string) {
+	t.Helper()
+
+	RequireVarErrors(t, Recover(fn), names...)
+}
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// Struct tags read by LoadStruct to validate fields. On slices, every tag except TagNonEmpty applies to the
+// elements of the slice.
+const (
+	// TagMin sets the minimum value of a numeric field. The tag is parsed with the parser of the field, so
+	// durations can use min:"1s".
+	TagMin = "min"
+	// TagMax sets the maximum value of a numeric field.
+	TagMax = "max"
+	// TagPattern sets a regular expression that string fields must match.
+	TagPattern = "pattern"
+	// TagFormat requires string fields to follow a known format: "url", "hostport" or "email".
+	TagFormat = "format"
+	// TagNonEmpty requires slices and maps to contain at least one element, when set to "true".
+	TagNonEmpty = "nonempty"
+)
+
+// Formats supported by TagFormat.
+const (
+	FormatURL      = "url"
+	FormatHostPort = "hostport"
+	FormatEmail    = "email"
+)
+
+var (
+	ErrOutOfRange      = errors.New("value out of range")
+	ErrPatternMismatch = errors.New("value does not match pattern")
+	ErrInvalidFormat   = errors.New("invalid format")
+	ErrEmptyValue      = errors.New("value is empty")
+)
+
+// Validator is implemented by configuration structs that need custom validation. LoadStruct calls Validate on the
+// loaded struct, and on every nested struct, once all the variables were successfully loaded.
+type Validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeFor[Validator]()
+
+// ValidateParser is a parsing function for LoadEnv, that runs a list of validators on the output of the sub-parser.
+//
+//	port := config.LoadEnv(os.Getenv("PORT"), 8080, config.ValidateParser(config.IntParser, config.Between(1, 65535)))
+func ValidateParser[T any](parser func(string) (T, error), validators ...func(T) error) func(string) (T, error) {
+	return func(value string) (T, error) {
+		parsedValue, err := parser(value)
+		if err != nil {
+			return parsedValue, err
+		}
+
+		for _, validator := range validators {
+			err = validator(parsedValue)
+			if err != nil {
+				return parsedValue, err
+			}
+		}
+
+		return parsedValue, nil
+	}
+}
+
+// Min returns a validator that rejects values lower than min.
+func Min[T cmp.Ordered](minValue T) func(T) error {
+	return func(value T) error {
+		if value < minValue {
+			return fmt.Errorf("%w: %v is lower than %v", ErrOutOfRange, value, minValue)
+		}
+
+		return nil
+	}
+}
+
+// Max returns a validator that rejects values greater than max.
+func Max[T cmp.Ordered](maxValue T) func(T) error {
+	return func(value T) error {
+		if value > maxValue {
+			return fmt.Errorf("%w: %v is greater than %v", ErrOutOfRange, value, maxValue)
+		}
+
+		return nil
+	}
+}
+
+// Between returns a validator that rejects values outside the [min, max] range.
+func Between[T cmp.Ordered](minValue, maxValue T) func(T) error {
+	lower, upper := Min(minValue), Max(maxValue)
+
+	return func(value T) error {
+		return errors.Join(lower(value), upper(value))
+	}
+}
+
+// Match returns a validator that rejects strings that do not match the regular expression.
+func Match(pattern *regexp.Regexp) func(string) error {
+	return func(value string) error {
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("%w: %q does not match %s", ErrPatternMismatch, value, pattern)
+		}
+
+		return nil
+	}
+}
+
+// IsURL is a validator that rejects strings that are not absolute URLs.
+func IsURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("%w: %q is not an absolute URL", ErrInvalidFormat, value)
+	}
+
+	return nil
+}
+
+// IsHostPort is a validator that rejects strings that are not in the "host:port" format.
+func IsHostPort(value string) error {
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	_, err = strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("%w: invalid port %q", ErrInvalidFormat, port)
+	}
+
+	return nil
+}
+
+// IsEmail is a validator that rejects strings that are not a bare email address.
+func IsEmail(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	if address.Address != value {
+		return fmt.Errorf("%w: %q is not a bare email address", ErrInvalidFormat, value)
+	}
+
+	return nil
+}
+
+// NotEmpty is a validator that rejects empty slices.
+func NotEmpty[T any](value []T) error {
+	if len(value) == 0 {
+		return ErrEmptyValue
+	}
+
+	return nil
+}
+
+var formatValidators = map[string]func(string) error{
+	FormatURL:      IsURL,
+	FormatHostPort: IsHostPort,
+	FormatEmail:    IsEmail,
+}
+
+// withValidation wraps a scalar parser with the validators set by struct tags.
+func withValidation(
+	parser func(string) (reflect.Value, error), valueType reflect.Type, tag reflect.StructTag,
+) (func(string) (reflect.Value, error), error) {
+	var validators []func(reflect.Value) error
+
+	for _, bound := range []struct {
+		name string
+		cmp  int
+	}{{TagMin, -1}, {TagMax, 1}} {
+		raw, ok := tag.Lookup(bound.name)
+		if !ok {
+			continue
+		}
+
+		limit, err := parser(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tag: %w", bound.name, err)
+		}
+
+		if _, ok = compareValues(limit, limit); !ok {
+			return nil, fmt.Errorf("%w: %s tag on %s", ErrUnsupportedType, bound.name, valueType)
+		}
+
+		validators = append(validators, func(value reflect.Value) error {
+			if result, _ := compareValues(value, limit); result == bound.cmp {
+				return fmt.Errorf("%w: %v is outside the %s of %v", ErrOutOfRange, value, bound.name, limit)
+			}
+
+			return nil
+		})
+	}
+
+	if raw, ok := tag.Lookup(TagPattern); ok {
+		pattern, err := regexp.Compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s tag: %w", TagPattern, err)
+		}
+
+		validators = append(validators, stringValidator(Match(pattern)))
+	}
+
+	if raw, ok := tag.Lookup(TagFormat); ok {
+		validator, ok := formatValidators[raw]
+		if !ok {
+			return nil, fmt.Errorf("invalid %s tag: unknown format %q", TagFormat, raw)
+		}
+
+		validators = append(validators, stringValidator(validator))
+	}
+
+	if len(validators) == 0 {
+		return parser, nil
+	}
+
+	return func(value string) (reflect.Value, error) {
+		parsed, err := parser(value)
+		if err != nil {
+			return parsed, err
+		}
+
+		for _, validator := range validators {
+			err = validator(parsed)
+			if err != nil {
+				return parsed, err
+			}
+		}
+
+		return parsed, nil
+	}, nil
+}
+
+// stringValidator applies a string validator to a reflected value.
+func stringValidator(validator func(string) error) func(reflect.Value) error {
+	return func(value reflect.Value) error {
+		return validator(fmt.Sprint(value.Interface()))
+	}
+}
+
+// compareValues compares two numeric values of the same kind. It returns false if the kind is not numeric.
+func compareValues(a, b reflect.Value) (int, bool) {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float()), true
+	default:
+		return 0, false
+	}
+}
+
+// validateStruct calls the Validate method of a struct and of its nested structs, children first.
+func validateStruct(value reflect.Value, path string) []error {
+	var errs []error
+
+	valueType := value.Type()
+
+	for i := range valueType.NumField() {
+		fieldType := valueType.Field(i)
+		if !fieldType.IsExported() || fieldType.Tag.Get(TagEnv) != "" || !isNestedStruct(fieldType.Type) {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				continue
+			}
+
+			fieldValue = fieldValue.Elem()
+		}
+
+		errs = append(errs, validateStruct(fieldValue, path+fieldType.Name+".")...)
+	}
+
+	if !reflect.PointerTo(valueType).Implements(validatorType) {
+		return errs
+	}
+
+	validator, _ := value.Addr().Interface().(Validator)
+
+	err := validator.Validate()
+	if err != nil {
+		name := valueType.Name()
+		if path != "" {
+			name = path[:len(path)-1]
+		}
+
+		errs = append(errs, fmt.Errorf("validate %s: %w", name, err))
+	}
+
+	return errs
+}
@@ -0,0 +1,153 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+var errValidateTest = errors.New("validate test")
+
+type validateTestNested struct {
+	Addr string `env:"ADDR"`
+}
+
+func (nested *validateTestNested) Validate() error {
+	if nested.Addr == "" {
+		return errValidateTest
+	}
+
+	return nil
+}
+
+type validateTestConfig struct {
+	Port    int                `env:"PORT"     max:"65535"        min:"1"`
+	Timeout time.Duration      `env:"TIMEOUT"  min:"1s"`
+	Name    string             `env:"NAME"     pattern:"^[a-z]+$"`
+	URL     string             `env:"URL"      format:"url"`
+	Host    string             `env:"HOST"     format:"hostport"`
+	Email   string             `env:"EMAIL"    format:"email"`
+	Hosts   []string           `env:"HOSTS"    format:"hostport"  nonempty:"true"`
+	SMTP    validateTestNested `prefix:"SMTP_"`
+}
+
+func TestResolveValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg validateTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"PORT":      "8080",
+			"TIMEOUT":   "5s",
+			"NAME":      "golib",
+			"URL":       "https://example.com/path",
+			"HOST":      "localhost:5432",
+			"EMAIL":     "user@example.com",
+			"HOSTS":     "a:1,b:2",
+			"SMTP_ADDR": "smtp:25",
+		})))
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg validateTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"PORT":      "0",
+			"TIMEOUT":   "10ms",
+			"NAME":      "GoLib",
+			"URL":       "/relative",
+			"HOST":      "localhost",
+			"EMAIL":     "User <user@example.com>",
+			"HOSTS":     "[]",
+			"SMTP_ADDR": "smtp:25",
+		})))
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+		require.ErrorIs(t, err, config.ErrPatternMismatch)
+		require.ErrorIs(t, err, config.ErrInvalidFormat)
+		require.ErrorIs(t, err, config.ErrEmptyValue)
+
+		names := make([]string, 0)
+		for _, varErr := range config.VarErrors(err) {
+			names = append(names, varErr.Name)
+		}
+
+		require.Equal(t, []string{"PORT", "TIMEOUT", "NAME", "URL", "HOST", "EMAIL", "HOSTS"}, names)
+	})
+
+	t.Run("Validator", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg validateTestConfig
+
+		err := config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{
+			"HOSTS": "a:1",
+		})))
+		require.ErrorIs(t, err, errValidateTest)
+		require.ErrorContains(t, err, "validate SMTP")
+	})
+
+	t.Run("NilSection", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			SMTP *validateTestNested `prefix:"SMTP_"`
+		}
+
+		require.NoError(t, config.LoadStruct(&cfg, config.WithLookup(mapLookup(map[string]string{}))))
+		require.Nil(t, cfg.SMTP)
+	})
+
+	t.Run("InvalidTag", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg struct {
+			Name string `env:"NAME" min:"1"`
+		}
+
+		require.ErrorIs(t, config.LoadStruct(&cfg), config.ErrUnsupportedType)
+	})
+}
+
+func TestEnvValidateParser(t *testing.T) {
+	t.Setenv("foo", "8080")
+	t.Setenv("bar", "0")
+	t.Setenv("baz", "abc")
+
+	portParser := config.ValidateParser(config.IntParser, config.Between(1, 65535))
+	nameParser := config.ValidateParser(config.StringParser, config.Match(regexp.MustCompile(`^[0-9]+$`)))
+
+	require.Equal(t, 8080, config.LoadEnv(os.Getenv("foo"), 3000, portParser))
+	require.Equal(t, 3000, config.LoadEnv(os.Getenv("bar"), 3000, portParser))
+	require.Equal(t, "8080", config.LoadEnv(os.Getenv("foo"), "0", nameParser))
+	require.Equal(t, "0", config.LoadEnv(os.Getenv("baz"), "0", nameParser))
+}
+
+func TestValidators(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, config.Min(1)(1))
+	require.ErrorIs(t, config.Min(1)(0), config.ErrOutOfRange)
+	require.NoError(t, config.Max(time.Second)(time.Second))
+	require.ErrorIs(t, config.Max(time.Second)(time.Minute), config.ErrOutOfRange)
+
+	require.NoError(t, config.IsURL("postgres://localhost:5432/db"))
+	require.ErrorIs(t, config.IsURL("localhost"), config.ErrInvalidFormat)
+	require.NoError(t, config.IsHostPort(":8080"))
+	require.ErrorIs(t, config.IsHostPort("localhost:http"), config.ErrInvalidFormat)
+	require.NoError(t, config.IsEmail("user@example.com"))
+	require.ErrorIs(t, config.IsEmail("user"), config.ErrInvalidFormat)
+	require.NoError(t, config.NotEmpty([]string{"a"}))
+	require.ErrorIs(t, config.NotEmpty([]string{}), config.ErrEmptyValue)
+}
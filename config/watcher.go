@@ -0,0 +1,236 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// WatcherOptions configures a Watcher.
+type WatcherOptions[T any] struct {
+	// Interval between two checks of the sources that implement WatchableSource. Polling is disabled if zero.
+	Interval time.Duration
+	// Signals that force a reload, e.g. syscall.SIGHUP.
+	Signals []os.Signal
+	// Validate is called on every new configuration. If it returns an error, the new configuration is discarded.
+	Validate func(value *T) error
+	// OnError is called when a reload triggered by Run fails. Defaults to logging the error with slog.
+	OnError func(err error)
+}
+
+// Watcher keeps a configuration up to date with its sources, and notifies subscribers when it changes.
+//
+//	watcher, err := config.NewWatcher(Config{}, config.WatcherOptions[Config]{
+//		Interval: 10 * time.Second,
+//		Signals:  []os.Signal{syscall.SIGHUP},
+//	}, config.WithSources(fileSource, &config.EnvSource{}))
+//
+//	config.SubscribeField(watcher, func(cfg Config) slog.Level { return cfg.LogLevel }, setLogLevel)
+//
+//	go watcher.Run(ctx)
+//
+// A failed reload, either because a variable is invalid or because validation failed, leaves the previous
+// configuration in place.
+type Watcher[T any] struct {
+	base    T
+	options WatcherOptions[T]
+	sources []Source
+	load    []StructOption
+
+	current     T
+	subscribers map[int]func(oldValue, newValue T)
+	lastID      int
+
+	mu       sync.RWMutex
+	reloadMu sync.Mutex
+}
+
+// NewWatcher loads the initial configuration, and returns a Watcher for it. Every reload starts from a copy of the
+// base value, so fields that are not set by any source keep their base value. The copy is shallow: nested structs
+// should not be referenced through pointers in the base value.
+func NewWatcher[T any](base T, options WatcherOptions[T], loadOptions ...StructOption) (*Watcher[T], error) {
+	watcher := &Watcher[T]{
+		base:        base,
+		options:     options,
+		sources:     newStructOptions(loadOptions).sources,
+		load:        loadOptions,
+		subscribers: make(map[int]func(oldValue, newValue T)),
+	}
+
+	if watcher.options.OnError == nil {
+		watcher.options.OnError = func(err error) {
+			slog.Error("reload configuration", slog.Any("error", err))
+		}
+	}
+
+	current, err := watcher.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher.current = current
+
+	return watcher, nil
+}
+
+// Current returns the current configuration.
+func (watcher *Watcher[T]) Current() T {
+	watcher.mu.RLock()
+	defer watcher.mu.RUnlock()
+
+	return watcher.current
+}
+
+// Subscribe registers a function called every time the configuration changes. The returned function removes the
+// subscription.
+func (watcher *Watcher[T]) Subscribe(fn func(oldValue, newValue T)) func() {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	watcher.lastID++
+	id := watcher.lastID
+	watcher.subscribers[id] = fn
+
+	return func() {
+		watcher.mu.Lock()
+		defer watcher.mu.Unlock()
+
+		delete(watcher.subscribers, id)
+	}
+}
+
+// Reload reads the sources again, and replaces the current configuration if it changed and is valid. Subscribers
+// are notified synchronously.
+func (watcher *Watcher[T]) Reload() error {
+	watcher.reloadMu.Lock()
+	defer watcher.reloadMu.Unlock()
+
+	next, err := watcher.resolve()
+	if err != nil {
+		return err
+	}
+
+	watcher.mu.Lock()
+
+	previous := watcher.current
+	if reflect.DeepEqual(previous, next) {
+		watcher.mu.Unlock()
+
+		return nil
+	}
+
+	watcher.current = next
+
+	subscribers := make([]func(oldValue, newValue T), 0, len(watcher.subscribers))
+	for _, subscriber := range watcher.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+
+	watcher.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(previous, next)
+	}
+
+	return nil
+}
+
+// Run watches the sources until the context is canceled. Sources are reloaded when one of the configured signals
+// is received, or when a WatchableSource reports a change.
+func (watcher *Watcher[T]) Run(ctx context.Context) error {
+	var ticks <-chan time.Time
+
+	if watcher.options.Interval > 0 {
+		ticker := time.NewTicker(watcher.options.Interval)
+		defer ticker.Stop()
+
+		ticks = ticker.C
+	}
+
+	signals := make(chan os.Signal, 1)
+
+	if len(watcher.options.Signals) > 0 {
+		signal.Notify(signals, watcher.options.Signals...)
+		defer signal.Stop(signals)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-signals:
+			watcher.reportError(watcher.Reload())
+		case <-ticks:
+			changed, err := watcher.changed()
+			if err != nil {
+				watcher.reportError(err)
+
+				continue
+			}
+
+			if changed {
+				watcher.reportError(watcher.Reload())
+			}
+		}
+	}
+}
+
+func (watcher *Watcher[T]) reportError(err error) {
+	if err != nil {
+		watcher.options.OnError(err)
+	}
+}
+
+// changed returns true if any watchable source changed.
+func (watcher *Watcher[T]) changed() (bool, error) {
+	for _, source := range watcher.sources {
+		watchable, ok := source.(WatchableSource)
+		if !ok {
+			continue
+		}
+
+		changed, err := watchable.Changed()
+		if err != nil {
+			return false, fmt.Errorf("check source %s: %w", source.Name(), err)
+		}
+
+		if changed {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (watcher *Watcher[T]) resolve() (T, error) {
+	value := watcher.base
+
+	err := LoadStruct(&value, watcher.load...)
+	if err != nil {
+		return value, err
+	}
+
+	if watcher.options.Validate != nil {
+		err = watcher.options.Validate(&value)
+		if err != nil {
+			return value, fmt.Errorf("validate configuration: %w", err)
+		}
+	}
+
+	return value, nil
+}
+
+// SubscribeField registers a function called every time a specific part of the configuration changes.
+func SubscribeField[T, V any](watcher *Watcher[T], selector func(T) V, fn func(oldValue, newValue V)) func() {
+	return watcher.Subscribe(func(oldValue, newValue T) {
+		oldField, newField := selector(oldValue), selector(newValue)
+		if !reflect.DeepEqual(oldField, newField) {
+			fn(oldField, newField)
+		}
+	})
+}
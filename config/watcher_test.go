@@ -0,0 +1,139 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+)
+
+var errWatcherInvalid = errors.New("invalid")
+
+type watcherTestConfig struct {
+	Level string  `env:"LEVEL"   json:"level"`
+	Rate  float64 `env:"RATE"    json:"rate"`
+	Name  string  `default:"app" env:"NAME"   json:"name"`
+}
+
+func writeWatcherFile(t *testing.T, path string, values map[string]any) {
+	t.Helper()
+
+	content, err := json.Marshal(values)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Reload", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatcherFile(t, path, map[string]any{"level": "info", "rate": 0.5})
+
+		watcher, err := config.NewWatcher(watcherTestConfig{}, config.WatcherOptions[watcherTestConfig]{
+			Validate: func(value *watcherTestConfig) error {
+				if value.Rate > 1 {
+					return errWatcherInvalid
+				}
+
+				return nil
+			},
+		}, config.WithSources(&config.FileSource{Path: path}))
+		require.NoError(t, err)
+
+		require.Equal(t, watcherTestConfig{Level: "info", Rate: 0.5, Name: "app"}, watcher.Current())
+
+		var (
+			changes      [][2]watcherTestConfig
+			levelChanges [][2]string
+		)
+
+		watcher.Subscribe(func(oldValue, newValue watcherTestConfig) {
+			changes = append(changes, [2]watcherTestConfig{oldValue, newValue})
+		})
+
+		selectLevel := func(cfg watcherTestConfig) string { return cfg.Level }
+		config.SubscribeField(watcher, selectLevel, func(oldValue, newValue string) {
+			levelChanges = append(levelChanges, [2]string{oldValue, newValue})
+		})
+
+		// Unchanged file: no notification.
+		require.NoError(t, watcher.Reload())
+		require.Empty(t, changes)
+
+		// Only the rate changes.
+		writeWatcherFile(t, path, map[string]any{"level": "info", "rate": 0.8})
+		require.NoError(t, watcher.Reload())
+		require.Len(t, changes, 1)
+		require.Empty(t, levelChanges)
+
+		// Invalid values keep the previous configuration.
+		writeWatcherFile(t, path, map[string]any{"level": "debug", "rate": 2})
+		require.ErrorIs(t, watcher.Reload(), errWatcherInvalid)
+		require.Equal(t, watcherTestConfig{Level: "info", Rate: 0.8, Name: "app"}, watcher.Current())
+
+		writeWatcherFile(t, path, map[string]any{"level": "debug", "rate": "fast"})
+		require.Error(t, watcher.Reload())
+		require.Equal(t, watcherTestConfig{Level: "info", Rate: 0.8, Name: "app"}, watcher.Current())
+
+		writeWatcherFile(t, path, map[string]any{"level": "debug", "rate": 0.8})
+		require.NoError(t, watcher.Reload())
+		require.Equal(t, [][2]string{{"info", "debug"}}, levelChanges)
+		require.Len(t, changes, 2)
+		require.Equal(t, watcherTestConfig{Level: "debug", Rate: 0.8, Name: "app"}, changes[1][1])
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatcherFile(t, path, map[string]any{"level": "info"})
+
+		watcher, err := config.NewWatcher(watcherTestConfig{}, config.WatcherOptions[watcherTestConfig]{
+			Interval: 10 * time.Millisecond,
+		}, config.WithSources(&config.FileSource{Path: path}))
+		require.NoError(t, err)
+
+		var (
+			level string
+			mu    sync.Mutex
+		)
+
+		config.SubscribeField(watcher, func(cfg watcherTestConfig) string { return cfg.Level }, func(_, newValue string) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			level = newValue
+		})
+
+		go func() {
+			_ = watcher.Run(t.Context())
+		}()
+
+		writeWatcherFile(t, path, map[string]any{"level": "warn"})
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return level == "warn"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("InvalidInitial", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.NewWatcher(watcherTestConfig{}, config.WatcherOptions[watcherTestConfig]{},
+			config.WithSources(&config.FileSource{Path: filepath.Join(t.TempDir(), "missing.json")}))
+		require.Error(t, err)
+	})
+}
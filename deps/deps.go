@@ -1,25 +1,9 @@
 package deps
 
 import (
-	"errors"
-	"fmt"
-	"strings"
-
-	"github.com/samber/lo"
+	"slices"
 )
 
-var ErrCircularDependency = errors.New("circular dependency detected")
-
-func printDepsGraph[Mod comparable](deps map[Mod]map[Mod]bool) string {
-	var output strings.Builder
-
-	for mod, localDeps := range deps {
-		output.WriteString(fmt.Sprintf("\n\t%v -> %v", mod, lo.Keys(localDeps)))
-	}
-
-	return output.String()
-}
-
 // ResolveDependants unwraps a flat list of dependencies, given a map of interdependent modules. It also prevents
 // circular dependencies.
 //
@@ -33,64 +17,78 @@ func printDepsGraph[Mod comparable](deps map[Mod]map[Mod]bool) string {
 //	mod1 -> dep1, dep2
 //	mod2 -> dep3, dep1, dep2
 func ResolveDependants[Mod comparable, Deps any](mods map[Mod][]Deps, deps map[Mod][]Mod) (map[Mod][]Deps, error) {
-	// https://dnaeon.github.io/dependency-graph-resolution-algorithm-in-go/
-	// Convert dependencies to a map. The algorithm performs better using maps behavior.
-	depsGraph := map[Mod]map[Mod]bool{}
-	for mod, localDeps := range deps {
-		depsGraph[mod] = map[Mod]bool{}
-
-		for _, dep := range localDeps {
-			depsGraph[mod][dep] = true
-		}
-	}
+	return ResolveDependantsWithOptions(mods, deps, Options[Deps]{})
+}
 
-	// To resolve every dependency, we first need to triage the mods regarding their depths: a mod without
-	// dependencies has a depth of 0, a mod with only dependencies of depth 0 has a depth of 1, and so on.
-	// Once we solved this triage, we can process mods in order, fully processing every mod of depth n before
-	// moving to depth n+1.
-	var resolvedMods []Mod
-
-	// We're going to resolve dependencies in rounds, unwrapping a single dependency depth at a time. Once the map
-	// of dependencies ie empty, this means we resolved everything, and can return the result.
-	for len(depsGraph) > 0 {
-		// A given depth n+1 must resolve to at least one node of depth n (because each level of depth depends on
-		// the previous one). If we can't find any node of depth n, then we have a circular dependency.
-		hasResolved := false
-
-		for mod, dependencies := range depsGraph {
-			if len(dependencies) > 0 {
-				continue
-			}
-
-			hasResolved = true
-
-			// A resolved node can be removed from the original graph, and added to the resolved graph.
-			delete(depsGraph, mod)
-			resolvedMods = append(resolvedMods, mod)
-
-			// Remove the resolve dependencies from the dependants of other nodes in the graph.
-			for _, dependantMod := range depsGraph {
-				delete(dependantMod, mod)
-			}
-		}
+// Options configures ResolveDependantsWithOptions.
+type Options[Deps any] struct {
+	// Compare sorts the resolved dependencies of each module. When nil, dependencies keep their declaration order:
+	// the dependencies of the module come first, followed by the inherited ones, in the order the modules they are
+	// inherited from are declared.
+	Compare func(a, b Deps) int
+	// Key de-duplicates the resolved dependencies of each module: only the first dependency with a given key is kept.
+	// The returned keys must be comparable. When nil, duplicates are kept.
+	Key func(dep Deps) any
+}
 
-		if !hasResolved {
-			return nil, fmt.Errorf("%w: %v", ErrCircularDependency, printDepsGraph(depsGraph))
-		}
+// ResolveDependantsWithOptions works like ResolveDependants, with options to sort and de-duplicate the resolved
+// dependencies, for example when a module inherits the same dependency from multiple paths (diamond dependencies).
+//
+//	resolved, err := deps.ResolveDependantsWithOptions(permissions, roles, deps.Options[string]{
+//		Compare: strings.Compare,
+//		Key:     func(permission string) any { return permission },
+//	})
+func ResolveDependantsWithOptions[Mod comparable, Deps any](
+	mods map[Mod][]Deps, deps map[Mod][]Mod, options Options[Deps],
+) (map[Mod][]Deps, error) {
+	graph, err := NewGraph(deps)
+	if err != nil {
+		return nil, err
 	}
 
-	// Now every mod is resolved, we can build the final map.
 	resolved := map[Mod][]Deps{}
 
-	for _, mod := range resolvedMods {
-		resolved[mod] = mods[mod]
+	for _, mod := range graph.Order() {
+		// Clone the slice, so appending inherited dependencies does not overwrite the backing array of mods.
+		resolved[mod] = slices.Clone(mods[mod])
 
 		// Because we resolve mods with the lowest depth first, we know that every dependency has been fully resolved
-		// when we reach a certain mod.
+		// when we reach a certain mod. Modules listed twice as a dependency are inherited twice, unless Key is set.
 		for _, dep := range deps[mod] {
 			resolved[mod] = append(resolved[mod], resolved[dep]...)
 		}
+
+		resolved[mod] = options.apply(resolved[mod])
 	}
 
 	return resolved, nil
 }
+
+// apply de-duplicates and sorts resolved dependencies.
+func (options Options[Deps]) apply(resolved []Deps) []Deps {
+	if options.Key != nil {
+		resolved = deduplicate(resolved, options.Key)
+	}
+
+	if options.Compare != nil {
+		slices.SortStableFunc(resolved, options.Compare)
+	}
+
+	return resolved
+}
+
+// deduplicate removes the dependencies whose key was already seen, preserving order.
+func deduplicate[Deps any](deps []Deps, key func(dep Deps) any) []Deps {
+	seen := make(map[any]bool, len(deps))
+
+	return slices.DeleteFunc(deps, func(dep Deps) bool {
+		depKey := key(dep)
+		if seen[depKey] {
+			return true
+		}
+
+		seen[depKey] = true
+
+		return false
+	})
+}
@@ -1,6 +1,7 @@
 package deps_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -69,6 +70,25 @@ func TestResolveDependants(t *testing.T) {
 				"mod:6": {"dep:7"},                                              // Depth: 0
 			},
 		},
+		{
+			name: "RepeatedDependency",
+
+			mods: map[string][]string{
+				"mod:1": {"dep:1"},
+				"mod:2": {"dep:2"},
+			},
+			deps: map[string][]string{
+				"mod:1": {},
+				"mod:2": {"mod:1", "mod:1"},
+			},
+
+			// Repeated dependencies are inherited as many times as they are listed. Use the Key option to remove
+			// the duplicates.
+			expect: map[string][]string{
+				"mod:1": {"dep:1"},
+				"mod:2": {"dep:2", "dep:1", "dep:1"},
+			},
+		},
 		{
 			name: "Circular/Direct",
 
@@ -111,3 +131,94 @@ func TestResolveDependants(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveDependantsWithOptions(t *testing.T) {
+	t.Parallel()
+
+	// Diamond: mod:4 inherits mod:1 through both mod:2 and mod:3.
+	mods := map[string][]string{
+		"mod:1": {"dep:2", "dep:1"},
+		"mod:2": {"dep:3"},
+		"mod:3": {"dep:4", "dep:1"},
+		"mod:4": {"dep:5"},
+	}
+	modDeps := map[string][]string{
+		"mod:1": {},
+		"mod:2": {"mod:1"},
+		"mod:3": {"mod:1"},
+		"mod:4": {"mod:2", "mod:3"},
+	}
+
+	testCases := []struct {
+		name string
+
+		options deps.Options[string]
+
+		expect map[string][]string
+	}{
+		{
+			name: "Default",
+
+			expect: map[string][]string{
+				"mod:1": {"dep:2", "dep:1"},
+				"mod:2": {"dep:3", "dep:2", "dep:1"},
+				"mod:3": {"dep:4", "dep:1", "dep:2", "dep:1"},
+				"mod:4": {"dep:5", "dep:3", "dep:2", "dep:1", "dep:4", "dep:1", "dep:2", "dep:1"},
+			},
+		},
+		{
+			name: "Key",
+
+			options: deps.Options[string]{
+				Key: func(dep string) any { return dep },
+			},
+
+			expect: map[string][]string{
+				"mod:1": {"dep:2", "dep:1"},
+				"mod:2": {"dep:3", "dep:2", "dep:1"},
+				"mod:3": {"dep:4", "dep:1", "dep:2"},
+				"mod:4": {"dep:5", "dep:3", "dep:2", "dep:1", "dep:4"},
+			},
+		},
+		{
+			name: "Compare",
+
+			options: deps.Options[string]{
+				Compare: strings.Compare,
+			},
+
+			expect: map[string][]string{
+				"mod:1": {"dep:1", "dep:2"},
+				"mod:2": {"dep:1", "dep:2", "dep:3"},
+				"mod:3": {"dep:1", "dep:1", "dep:2", "dep:4"},
+				"mod:4": {"dep:1", "dep:1", "dep:1", "dep:2", "dep:2", "dep:3", "dep:4", "dep:5"},
+			},
+		},
+		{
+			name: "KeyAndCompare",
+
+			options: deps.Options[string]{
+				Compare: strings.Compare,
+				Key:     func(dep string) any { return dep },
+			},
+
+			expect: map[string][]string{
+				"mod:1": {"dep:1", "dep:2"},
+				"mod:2": {"dep:1", "dep:2", "dep:3"},
+				"mod:3": {"dep:1", "dep:2", "dep:4"},
+				"mod:4": {"dep:1", "dep:2", "dep:3", "dep:4", "dep:5"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := deps.ResolveDependantsWithOptions(mods, modDeps, testCase.options)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, resolved)
+			require.Equal(t, []string{"dep:2", "dep:1"}, mods["mod:1"])
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package deps
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+)
+
+// Edge is a dependency of a module on another, for ResolveEdges.
+type Edge[Mod comparable, Deps any] struct {
+	// To is the module depended on.
+	To Mod
+	// Optional edges to modules that are not declared are ignored, instead of failing the resolution.
+	Optional bool
+	// Label describes the edge in exported graphs.
+	Label string
+	// Weight orders inheritance: dependencies inherited through edges of lower weight come first. Edges of equal
+	// weight keep their declaration order.
+	Weight int
+	// Filter selects the dependencies inherited through the edge. Every dependency is inherited if nil.
+	Filter func(dep Deps) bool
+}
+
+// ResolveEdges works like ResolveDependantsWithOptions, with dependencies described by edges. Every module must be
+// a key of edges, including modules without dependencies.
+//
+//	resolved, err := deps.ResolveEdges(permissions, map[string][]deps.Edge[string, string]{
+//		"support": {
+//			{To: "admin", Filter: func(permission string) bool { return permission != "delete" }},
+//			{To: "billing", Optional: true},
+//		},
+//		"admin": {},
+//	}, deps.Options[string]{})
+func ResolveEdges[Mod comparable, Deps any](
+	mods map[Mod][]Deps, edges map[Mod][]Edge[Mod, Deps], options Options[Deps],
+) (map[Mod][]Deps, error) {
+	graph, err := NewGraph(EdgeDependencies(edges))
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := map[Mod][]Deps{}
+
+	for _, mod := range graph.Order() {
+		resolved[mod] = slices.Clone(mods[mod])
+
+		for _, edge := range sortedEdges(edges, mod) {
+			for _, dep := range resolved[edge.To] {
+				if edge.Filter == nil || edge.Filter(dep) {
+					resolved[mod] = append(resolved[mod], dep)
+				}
+			}
+		}
+
+		resolved[mod] = options.apply(resolved[mod])
+	}
+
+	return resolved, nil
+}
+
+// EdgeDependencies converts edges to the dependencies of each module, as expected by NewGraph, WriteDOT and
+// WriteMermaid. Optional edges to undeclared modules are removed.
+func EdgeDependencies[Mod comparable, Deps any](edges map[Mod][]Edge[Mod, Deps]) map[Mod][]Mod {
+	deps := make(map[Mod][]Mod, len(edges))
+
+	for mod := range edges {
+		deps[mod] = []Mod{}
+
+		for _, edge := range sortedEdges(edges, mod) {
+			if !slices.Contains(deps[mod], edge.To) {
+				deps[mod] = append(deps[mod], edge.To)
+			}
+		}
+	}
+
+	return deps
+}
+
+// EdgeLabels returns a function that labels exported edges with their label and weight, for ExportOptions.
+func EdgeLabels[Mod comparable, Deps any](edges map[Mod][]Edge[Mod, Deps]) func(from, to Mod) string {
+	return func(from, to Mod) string {
+		for _, edge := range edges[from] {
+			if edge.To != to {
+				continue
+			}
+
+			switch {
+			case edge.Weight == 0:
+				return edge.Label
+			case edge.Label == "":
+				return strconv.Itoa(edge.Weight)
+			default:
+				return edge.Label + " (" + strconv.Itoa(edge.Weight) + ")"
+			}
+		}
+
+		return ""
+	}
+}
+
+// sortedEdges returns the edges of a module by weight, without the optional edges to undeclared modules.
+func sortedEdges[Mod comparable, Deps any](edges map[Mod][]Edge[Mod, Deps], mod Mod) []Edge[Mod, Deps] {
+	sorted := slices.DeleteFunc(slices.Clone(edges[mod]), func(edge Edge[Mod, Deps]) bool {
+		_, declared := edges[edge.To]
+
+		return edge.Optional && !declared
+	})
+
+	slices.SortStableFunc(sorted, func(a, b Edge[Mod, Deps]) int {
+		return cmp.Compare(a.Weight, b.Weight)
+	})
+
+	return sorted
+}
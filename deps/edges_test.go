@@ -0,0 +1,138 @@
+package deps_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestResolveEdges(t *testing.T) {
+	t.Parallel()
+
+	permissions := map[string][]string{
+		"admin":   {"delete", "write"},
+		"billing": {"invoice"},
+		"reader":  {"read"},
+		"support": {"ticket"},
+	}
+
+	notDelete := func(permission string) bool { return permission != "delete" }
+
+	testCases := []struct {
+		name string
+
+		edges map[string][]deps.Edge[string, string]
+
+		expect    map[string][]string
+		expectErr error
+	}{
+		{
+			name: "Filter",
+
+			edges: map[string][]deps.Edge[string, string]{
+				"admin":   {{To: "reader"}},
+				"reader":  {},
+				"support": {{To: "admin", Filter: notDelete}},
+			},
+
+			expect: map[string][]string{
+				"admin":   {"delete", "write", "read"},
+				"reader":  {"read"},
+				"support": {"ticket", "write", "read"},
+			},
+		},
+		{
+			name: "Weight",
+
+			edges: map[string][]deps.Edge[string, string]{
+				"admin":   {},
+				"billing": {},
+				"reader":  {},
+				"support": {{To: "admin", Weight: 2}, {To: "reader"}, {To: "billing", Weight: 1}},
+			},
+
+			expect: map[string][]string{
+				"admin":   {"delete", "write"},
+				"billing": {"invoice"},
+				"reader":  {"read"},
+				"support": {"ticket", "read", "invoice", "delete", "write"},
+			},
+		},
+		{
+			name: "Optional",
+
+			edges: map[string][]deps.Edge[string, string]{
+				"reader":  {},
+				"support": {{To: "billing", Optional: true}, {To: "reader"}},
+			},
+
+			expect: map[string][]string{
+				"reader":  {"read"},
+				"support": {"ticket", "read"},
+			},
+		},
+		{
+			name: "Required",
+
+			edges: map[string][]deps.Edge[string, string]{
+				"reader":  {},
+				"support": {{To: "billing"}, {To: "reader"}},
+			},
+
+			expectErr: deps.ErrUndeclaredDependency,
+		},
+		{
+			name: "Circular",
+
+			edges: map[string][]deps.Edge[string, string]{
+				"admin":   {{To: "support", Optional: true}},
+				"support": {{To: "admin", Filter: notDelete}},
+			},
+
+			expectErr: deps.ErrCircularDependency,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := deps.ResolveEdges(permissions, testCase.edges, deps.Options[string]{})
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, resolved)
+		})
+	}
+}
+
+func TestEdgeExport(t *testing.T) {
+	t.Parallel()
+
+	edges := map[string][]deps.Edge[string, string]{
+		"admin":   {},
+		"reader":  {},
+		"support": {{To: "admin", Label: "no delete", Weight: 2}, {To: "reader", Weight: 1}, {To: "billing", Optional: true}},
+	}
+
+	require.Equal(t, map[string][]string{
+		"admin":   {},
+		"reader":  {},
+		"support": {"reader", "admin"},
+	}, deps.EdgeDependencies(edges))
+
+	var output strings.Builder
+
+	require.NoError(t, deps.WriteMermaid(&output, deps.EdgeDependencies(edges), deps.ExportOptions[string]{
+		EdgeLabel: deps.EdgeLabels(edges),
+	}))
+
+	require.Equal(t, `flowchart TD
+  n0["admin"]
+  n1["reader"]
+  n2["support"]
+  n2 -->|"1"| n1
+  n2 -->|"no delete (2)"| n0
+`, output.String())
+}
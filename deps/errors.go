@@ -0,0 +1,189 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrCircularDependency   = errors.New("circular dependency detected")
+	ErrSelfDependency       = errors.New("module depends on itself")
+	ErrUndeclaredDependency = errors.New("dependency on undeclared module")
+)
+
+// CircularDependencyError is returned when a dependency graph contains cycles. It matches ErrCircularDependency
+// with errors.Is.
+type CircularDependencyError[Mod comparable] struct {
+	// Cycles found in the graph. Each cycle lists the modules in dependency order, starting with the one whose
+	// formatted name sorts first: [a, b, c] means a depends on b, b on c, and c on a.
+	Cycles [][]Mod
+}
+
+func (err *CircularDependencyError[Mod]) Error() string {
+	cycles := make([]string, len(err.Cycles))
+
+	for i, cycle := range err.Cycles {
+		var output strings.Builder
+
+		for _, mod := range cycle {
+			output.WriteString(fmt.Sprintf("%v -> ", mod))
+		}
+
+		output.WriteString(fmt.Sprint(cycle[0]))
+
+		cycles[i] = output.String()
+	}
+
+	return ErrCircularDependency.Error() + ": " + strings.Join(cycles, "; ")
+}
+
+func (err *CircularDependencyError[Mod]) Is(target error) bool {
+	return target == ErrCircularDependency
+}
+
+// checkDeclarations reports modules that depend on themselves, or on modules that are not keys of the map.
+func checkDeclarations[Mod comparable](deps map[Mod][]Mod) error {
+	var errs []error
+
+	for mod, localDeps := range deps {
+		for _, dep := range localDeps {
+			switch _, declared := deps[dep]; {
+			case dep == mod:
+				errs = append(errs, fmt.Errorf("%w: %v", ErrSelfDependency, mod))
+			case !declared:
+				errs = append(errs, fmt.Errorf("%w: %v depends on %v", ErrUndeclaredDependency, mod, dep))
+			}
+		}
+	}
+
+	// Sort errors so the output does not depend on the iteration order of the map.
+	slices.SortFunc(errs, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+
+	return errors.Join(errs...)
+}
+
+// findCycles returns a cycle for every strongly connected component of the graph that has more than one module.
+// The graph must not contain self-dependencies.
+func findCycles[Mod comparable](graph map[Mod]map[Mod]bool) [][]Mod {
+	var cycles [][]Mod
+
+	for _, component := range stronglyConnectedComponents(graph) {
+		if len(component) < 2 {
+			continue
+		}
+
+		members := make(map[Mod]bool, len(component))
+		for _, mod := range component {
+			members[mod] = true
+		}
+
+		start := slices.MinFunc(component, compareFormatted[Mod])
+		cycles = append(cycles, shortestCycle(graph, members, start))
+	}
+
+	slices.SortFunc(cycles, func(a, b []Mod) int {
+		return compareFormatted(a[0], b[0])
+	})
+
+	return cycles
+}
+
+// shortestCycle returns the shortest path from start back to itself, within the members of a strongly connected
+// component.
+func shortestCycle[Mod comparable](graph map[Mod]map[Mod]bool, members map[Mod]bool, start Mod) []Mod {
+	parents := map[Mod]Mod{}
+	queue := []Mod{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for next := range graph[current] {
+			if !members[next] {
+				continue
+			}
+
+			if next == start {
+				cycle := []Mod{current}
+				for cycle[0] != start {
+					cycle = append([]Mod{parents[cycle[0]]}, cycle...)
+				}
+
+				return cycle
+			}
+
+			if _, visited := parents[next]; !visited {
+				parents[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return []Mod{start}
+}
+
+// stronglyConnectedComponents implements Tarjan's algorithm.
+func stronglyConnectedComponents[Mod comparable](graph map[Mod]map[Mod]bool) [][]Mod {
+	var (
+		index      int
+		stack      []Mod
+		components [][]Mod
+		visit      func(mod Mod)
+	)
+
+	indexes := map[Mod]int{}
+	lowLinks := map[Mod]int{}
+	onStack := map[Mod]bool{}
+
+	visit = func(mod Mod) {
+		indexes[mod], lowLinks[mod] = index, index
+		index++
+
+		stack = append(stack, mod)
+		onStack[mod] = true
+
+		for dep := range graph[mod] {
+			if _, visited := indexes[dep]; !visited {
+				visit(dep)
+				lowLinks[mod] = min(lowLinks[mod], lowLinks[dep])
+			} else if onStack[dep] {
+				lowLinks[mod] = min(lowLinks[mod], indexes[dep])
+			}
+		}
+
+		if lowLinks[mod] != indexes[mod] {
+			return
+		}
+
+		var component []Mod
+
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+
+			if last == mod {
+				break
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	for mod := range graph {
+		if _, visited := indexes[mod]; !visited {
+			visit(mod)
+		}
+	}
+
+	return components
+}
+
+func compareFormatted[Mod comparable](a, b Mod) int {
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
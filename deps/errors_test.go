@@ -0,0 +1,124 @@
+package deps_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestNewGraphErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+
+		deps map[string][]string
+
+		expectCycles [][]string
+		expectErrs   []error
+		expectMsg    string
+	}{
+		{
+			name: "Cycle",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:2"},
+				"mod:2": {"mod:3"},
+				"mod:3": {"mod:1"},
+				"mod:4": {"mod:3"},
+				"mod:5": {},
+			},
+
+			expectCycles: [][]string{{"mod:1", "mod:2", "mod:3"}},
+			expectErrs:   []error{deps.ErrCircularDependency},
+			expectMsg:    "circular dependency detected: mod:1 -> mod:2 -> mod:3 -> mod:1",
+		},
+		{
+			name: "Cycle/Rotated",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:3"},
+				"mod:2": {"mod:1"},
+				"mod:3": {"mod:2"},
+			},
+
+			expectCycles: [][]string{{"mod:1", "mod:3", "mod:2"}},
+			expectErrs:   []error{deps.ErrCircularDependency},
+			expectMsg:    "circular dependency detected: mod:1 -> mod:3 -> mod:2 -> mod:1",
+		},
+		{
+			name: "Cycle/Multiple",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:2"},
+				"mod:2": {"mod:1"},
+				"mod:3": {"mod:4", "mod:1"},
+				"mod:4": {"mod:3"},
+			},
+
+			expectCycles: [][]string{{"mod:1", "mod:2"}, {"mod:3", "mod:4"}},
+			expectErrs:   []error{deps.ErrCircularDependency},
+			expectMsg:    "circular dependency detected: mod:1 -> mod:2 -> mod:1; mod:3 -> mod:4 -> mod:3",
+		},
+		{
+			name: "SelfDependency",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:1"},
+				"mod:2": {},
+			},
+
+			expectErrs: []error{deps.ErrSelfDependency},
+			expectMsg:  "module depends on itself: mod:1",
+		},
+		{
+			name: "Undeclared",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:2"},
+			},
+
+			expectErrs: []error{deps.ErrUndeclaredDependency},
+			expectMsg:  "dependency on undeclared module: mod:1 depends on mod:2",
+		},
+		{
+			name: "Declarations",
+
+			deps: map[string][]string{
+				"mod:1": {"mod:1", "mod:3"},
+				"mod:2": {"mod:2"},
+			},
+
+			expectErrs: []error{deps.ErrSelfDependency, deps.ErrUndeclaredDependency},
+			expectMsg: "dependency on undeclared module: mod:1 depends on mod:3\n" +
+				"module depends on itself: mod:1\n" +
+				"module depends on itself: mod:2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := deps.NewGraph(testCase.deps)
+
+			for _, expectErr := range testCase.expectErrs {
+				require.ErrorIs(t, err, expectErr)
+			}
+
+			require.EqualError(t, err, testCase.expectMsg)
+
+			var cycleErr *deps.CircularDependencyError[string]
+			if testCase.expectCycles == nil {
+				require.NotErrorAs(t, err, &cycleErr)
+
+				return
+			}
+
+			require.ErrorAs(t, err, &cycleErr)
+			require.Equal(t, testCase.expectCycles, cycleErr.Cycles)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// ExecuteOptions configures Execute.
+type ExecuteOptions struct {
+	// Workers is the maximum number of modules processed concurrently. There is no limit if zero.
+	Workers int
+}
+
+// Result is the outcome of a module processed by Execute.
+type Result struct {
+	// Err returned by the module. When the module was skipped, it wraps ErrDependencyFailed, or the error of the
+	// context.
+	Err error
+	// Skipped is true if the module did not run, because one of its dependencies failed or the context was canceled.
+	Skipped bool
+	// Start is the time the module started running.
+	Start time.Time
+	// Duration of the run.
+	Duration time.Duration
+}
+
+type completion[Mod comparable] struct {
+	mod    Mod
+	result *Result
+}
+
+// Execute runs fn for every module of a graph, concurrently. A module runs as soon as all its dependencies succeeded:
+// if one of them fails, the module and its transitive dependants are skipped, while independent modules keep
+// running. Modules that have not started when the context is canceled are skipped.
+//
+// It returns the result of every module, and joins the errors of the modules that failed.
+//
+//	results, err := deps.Execute(ctx, graph, func(ctx context.Context, table string) error {
+//		return backfill(ctx, table)
+//	}, deps.ExecuteOptions{Workers: 4})
+func Execute[Mod comparable](
+	ctx context.Context, graph *Graph[Mod], fn func(ctx context.Context, mod Mod) error, options ExecuteOptions,
+) (map[Mod]*Result, error) {
+	results := make(map[Mod]*Result, graph.Len())
+	pending := make(map[Mod]int, graph.Len())
+	blocked := make(map[Mod]bool)
+
+	for mod, dependencies := range graph.deps {
+		pending[mod] = len(dependencies)
+	}
+
+	var ready []Mod
+	if len(graph.layers) > 0 {
+		ready = slices.Clone(graph.layers[0])
+	}
+
+	remaining := graph.Len()
+
+	// finish releases the dependants of a module once it is done. Dependants of a failed module are skipped, which
+	// in turn releases their own dependants.
+	var finish func(mod Mod, failed bool)
+
+	finish = func(mod Mod, failed bool) {
+		remaining--
+
+		for _, dependant := range graph.dependants[mod] {
+			pending[dependant]--
+			blocked[dependant] = blocked[dependant] || failed
+
+			if pending[dependant] > 0 {
+				continue
+			}
+
+			if blocked[dependant] {
+				results[dependant] = &Result{Err: ErrDependencyFailed, Skipped: true}
+				finish(dependant, true)
+
+				continue
+			}
+
+			ready = append(ready, dependant)
+		}
+	}
+
+	done := make(chan completion[Mod])
+	running := 0
+
+	for remaining > 0 {
+		for len(ready) > 0 && (options.Workers <= 0 || running < options.Workers) {
+			mod := ready[0]
+			ready = ready[1:]
+
+			if ctx.Err() != nil {
+				results[mod] = &Result{Err: ctx.Err(), Skipped: true}
+				finish(mod, true)
+
+				continue
+			}
+
+			running++
+
+			go func() {
+				start := time.Now()
+				err := fn(ctx, mod)
+				done <- completion[Mod]{
+					mod:    mod,
+					result: &Result{Err: err, Start: start, Duration: time.Since(start)},
+				}
+			}()
+		}
+
+		// Every module may have been skipped.
+		if running == 0 {
+			break
+		}
+
+		completed := <-done
+		running--
+
+		results[completed.mod] = completed.result
+		finish(completed.mod, completed.result.Err != nil)
+	}
+
+	return results, executeError(ctx, results)
+}
+
+// executeError joins the errors of the modules that failed, in a stable order.
+func executeError[Mod comparable](ctx context.Context, results map[Mod]*Result) error {
+	var (
+		failed   []Mod
+		canceled bool
+	)
+
+	for mod, result := range results {
+		switch {
+		case result.Err == nil:
+		case result.Skipped:
+			canceled = canceled || !errors.Is(result.Err, ErrDependencyFailed)
+		default:
+			failed = append(failed, mod)
+		}
+	}
+
+	slices.SortFunc(failed, compareFormatted[Mod])
+
+	errs := make([]error, 0, len(failed)+1)
+	for _, mod := range failed {
+		errs = append(errs, fmt.Errorf("%v: %w", mod, results[mod].Err))
+	}
+
+	if canceled {
+		errs = append(errs, ctx.Err())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package deps_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	graph, err := deps.NewGraph(graphTestDeps)
+	require.NoError(t, err)
+
+	t.Run("Order", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			finished   = map[string]bool{}
+			violations []string
+			mu         sync.Mutex
+		)
+
+		results, err := deps.Execute(t.Context(), graph, func(_ context.Context, mod string) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, dep := range graph.Dependencies(mod) {
+				if !finished[dep] {
+					violations = append(violations, mod+" started before "+dep)
+				}
+			}
+
+			finished[mod] = true
+
+			return nil
+		}, deps.ExecuteOptions{})
+		require.NoError(t, err)
+
+		require.Empty(t, violations)
+		require.Len(t, results, graph.Len())
+
+		for mod, result := range results {
+			require.NoError(t, result.Err, mod)
+			require.False(t, result.Skipped, mod)
+			require.False(t, result.Start.IsZero(), mod)
+		}
+	})
+
+	t.Run("Workers", func(t *testing.T) {
+		t.Parallel()
+
+		var running, maxRunning atomic.Int32
+
+		_, err := deps.Execute(t.Context(), graph, func(context.Context, string) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for previous := maxRunning.Load(); current > previous; previous = maxRunning.Load() {
+				maxRunning.CompareAndSwap(previous, current)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		}, deps.ExecuteOptions{Workers: 1})
+		require.NoError(t, err)
+
+		require.Equal(t, int32(1), maxRunning.Load())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		var ran sync.Map
+
+		results, err := deps.Execute(t.Context(), graph, func(_ context.Context, mod string) error {
+			ran.Store(mod, true)
+
+			if mod == "mod:1" {
+				return errComponent
+			}
+
+			return nil
+		}, deps.ExecuteOptions{Workers: 2})
+		require.ErrorIs(t, err, errComponent)
+		require.ErrorContains(t, err, "mod:1")
+		require.NotErrorIs(t, err, deps.ErrDependencyFailed)
+
+		require.ErrorIs(t, results["mod:1"].Err, errComponent)
+		require.False(t, results["mod:1"].Skipped)
+
+		// mod:4 depends on mod:1, and mod:3 on mod:4.
+		for _, mod := range []string{"mod:3", "mod:4"} {
+			require.ErrorIs(t, results[mod].Err, deps.ErrDependencyFailed, mod)
+			require.True(t, results[mod].Skipped, mod)
+
+			_, ok := ran.Load(mod)
+			require.False(t, ok, mod)
+		}
+
+		for _, mod := range []string{"mod:2", "mod:5", "mod:6"} {
+			require.NoError(t, results[mod].Err, mod)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		results, err := deps.Execute(ctx, graph, func(context.Context, string) error {
+			return nil
+		}, deps.ExecuteOptions{})
+		require.ErrorIs(t, err, context.Canceled)
+
+		require.Len(t, results, graph.Len())
+
+		for mod, result := range results {
+			require.True(t, result.Skipped, mod)
+		}
+	})
+}
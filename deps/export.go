@@ -0,0 +1,219 @@
+package deps
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cycleColor highlights the modules and dependencies that are part of a cycle.
+const cycleColor = "#d00000"
+
+// ExportOptions configures WriteDOT and WriteMermaid.
+type ExportOptions[Mod comparable] struct {
+	// Label returns the text displayed for a module. Defaults to fmt.Sprint. Use ResolvedLabel to display the
+	// dependencies resolved by ResolveDependants.
+	Label func(mod Mod) string
+	// EdgeLabel returns the text displayed on the edge from a module to one of its dependencies, if any. Use
+	// EdgeLabels to display the labels and weights of edges.
+	EdgeLabel func(from, to Mod) string
+}
+
+// ResolvedLabel returns a label that displays each module with its resolved dependencies, on separate lines.
+func ResolvedLabel[Mod comparable, Deps any](resolved map[Mod][]Deps) func(mod Mod) string {
+	return func(mod Mod) string {
+		lines := []string{fmt.Sprint(mod)}
+		for _, dep := range resolved[mod] {
+			lines = append(lines, fmt.Sprint(dep))
+		}
+
+		return strings.Join(lines, "\n")
+	}
+}
+
+// WriteDOT writes a dependency graph in the Graphviz DOT format, with an arrow from each module to the modules it
+// depends on. The graph is not resolved, so cycles can be rendered: they are highlighted in red.
+func WriteDOT[Mod comparable](w io.Writer, deps map[Mod][]Mod, options ExportOptions[Mod]) error {
+	graph := newExportGraph(deps, options)
+
+	var builder strings.Builder
+
+	builder.WriteString("digraph deps {\n")
+
+	for i, mod := range graph.nodes {
+		attributes := "label=" + strconv.Quote(graph.label(mod))
+		if graph.cyclic[mod] {
+			attributes += ", color=" + strconv.Quote(cycleColor)
+		}
+
+		builder.WriteString(fmt.Sprintf("  n%d [%s];\n", i, attributes))
+	}
+
+	for _, edge := range graph.edges {
+		builder.WriteString(fmt.Sprintf("  n%d -> n%d", graph.ids[edge.from], graph.ids[edge.to]))
+
+		var attributes []string
+		if edge.label != "" {
+			attributes = append(attributes, "label="+strconv.Quote(edge.label))
+		}
+
+		if edge.cycle {
+			attributes = append(attributes, "color="+strconv.Quote(cycleColor))
+		}
+
+		if len(attributes) > 0 {
+			builder.WriteString(" [" + strings.Join(attributes, ", ") + "]")
+		}
+
+		builder.WriteString(";\n")
+	}
+
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+// WriteMermaid writes a dependency graph as a Mermaid flowchart, with an arrow from each module to the modules it
+// depends on. The graph is not resolved, so cycles can be rendered: they are highlighted in red.
+func WriteMermaid[Mod comparable](w io.Writer, deps map[Mod][]Mod, options ExportOptions[Mod]) error {
+	graph := newExportGraph(deps, options)
+
+	var (
+		builder     strings.Builder
+		cyclicNodes []string
+		cyclicEdges []string
+	)
+
+	builder.WriteString("flowchart TD\n")
+
+	for i, mod := range graph.nodes {
+		builder.WriteString(fmt.Sprintf("  n%d[\"%s\"]\n", i, mermaidLabel(graph.label(mod))))
+
+		if graph.cyclic[mod] {
+			cyclicNodes = append(cyclicNodes, fmt.Sprintf("n%d", i))
+		}
+	}
+
+	for i, edge := range graph.edges {
+		arrow := "-->"
+		if edge.label != "" {
+			arrow = "-->|\"" + mermaidLabel(edge.label) + "\"|"
+		}
+
+		builder.WriteString(fmt.Sprintf("  n%d %s n%d\n", graph.ids[edge.from], arrow, graph.ids[edge.to]))
+
+		if edge.cycle {
+			cyclicEdges = append(cyclicEdges, strconv.Itoa(i))
+		}
+	}
+
+	if len(cyclicNodes) > 0 {
+		builder.WriteString("  classDef cycle stroke:" + cycleColor + ",stroke-width:2px\n")
+		builder.WriteString("  class " + strings.Join(cyclicNodes, ",") + " cycle\n")
+	}
+
+	if len(cyclicEdges) > 0 {
+		builder.WriteString("  linkStyle " + strings.Join(cyclicEdges, ",") + " stroke:" + cycleColor + "\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+type exportEdge[Mod comparable] struct {
+	from, to Mod
+	label    string
+	cycle    bool
+}
+
+// exportGraph is a dependency graph with a stable order, for exporters.
+type exportGraph[Mod comparable] struct {
+	// Modules, sorted by their formatted name. Undeclared dependencies are included.
+	nodes []Mod
+	ids   map[Mod]int
+	edges []exportEdge[Mod]
+	// Modules that are part of a cycle.
+	cyclic map[Mod]bool
+	label  func(mod Mod) string
+}
+
+func newExportGraph[Mod comparable](deps map[Mod][]Mod, options ExportOptions[Mod]) *exportGraph[Mod] {
+	graph := &exportGraph[Mod]{
+		ids:    make(map[Mod]int),
+		cyclic: make(map[Mod]bool),
+		label:  options.Label,
+	}
+
+	if graph.label == nil {
+		graph.label = func(mod Mod) string { return fmt.Sprint(mod) }
+	}
+
+	depsGraph := make(map[Mod]map[Mod]bool, len(deps))
+
+	for mod, localDeps := range deps {
+		depsGraph[mod] = make(map[Mod]bool, len(localDeps))
+
+		for _, dep := range localDeps {
+			depsGraph[mod][dep] = true
+
+			if _, ok := depsGraph[dep]; !ok {
+				depsGraph[dep] = make(map[Mod]bool)
+			}
+		}
+	}
+
+	for mod := range depsGraph {
+		graph.nodes = append(graph.nodes, mod)
+	}
+
+	slices.SortFunc(graph.nodes, compareFormatted[Mod])
+
+	for i, mod := range graph.nodes {
+		graph.ids[mod] = i
+	}
+
+	// Two modules of the same strongly connected component depend on each other, directly or not.
+	component := make(map[Mod]int, len(depsGraph))
+
+	for i, members := range stronglyConnectedComponents(depsGraph) {
+		for _, mod := range members {
+			component[mod] = i
+			graph.cyclic[mod] = len(members) > 1 || depsGraph[mod][mod]
+		}
+	}
+
+	for _, mod := range graph.nodes {
+		seen := make(map[Mod]bool, len(deps[mod]))
+
+		for _, dep := range deps[mod] {
+			if seen[dep] {
+				continue
+			}
+
+			seen[dep] = true
+			edge := exportEdge[Mod]{
+				from:  mod,
+				to:    dep,
+				cycle: component[mod] == component[dep] && graph.cyclic[mod],
+			}
+
+			if options.EdgeLabel != nil {
+				edge.label = options.EdgeLabel(mod, dep)
+			}
+
+			graph.edges = append(graph.edges, edge)
+		}
+	}
+
+	return graph
+}
+
+// mermaidLabel escapes a label for a quoted Mermaid node.
+func mermaidLabel(label string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(label)
+}
@@ -0,0 +1,119 @@
+package deps_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestWriteDOT(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Graph", func(t *testing.T) {
+		t.Parallel()
+
+		resolved, err := deps.ResolveDependants(map[string][]string{
+			"admin":  {"delete"},
+			"editor": {"write"},
+			"reader": {"read"},
+		}, map[string][]string{
+			"admin":  {"editor"},
+			"editor": {"reader"},
+			"reader": {},
+		})
+		require.NoError(t, err)
+
+		var output strings.Builder
+
+		require.NoError(t, deps.WriteDOT(&output, map[string][]string{
+			"admin":  {"editor"},
+			"editor": {"reader"},
+			"reader": {},
+		}, deps.ExportOptions[string]{Label: deps.ResolvedLabel(resolved)}))
+
+		require.Equal(t, `digraph deps {
+  n0 [label="admin\ndelete\nwrite\nread"];
+  n1 [label="editor\nwrite\nread"];
+  n2 [label="reader\nread"];
+  n0 -> n1;
+  n1 -> n2;
+}
+`, output.String())
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		t.Parallel()
+
+		var output strings.Builder
+
+		require.NoError(t, deps.WriteDOT(&output, map[string][]string{
+			"a": {"b", "d"},
+			"b": {"c"},
+			"c": {"a"},
+			"d": {"d", "e"},
+		}, deps.ExportOptions[string]{}))
+
+		require.Equal(t, `digraph deps {
+  n0 [label="a", color="#d00000"];
+  n1 [label="b", color="#d00000"];
+  n2 [label="c", color="#d00000"];
+  n3 [label="d", color="#d00000"];
+  n4 [label="e"];
+  n0 -> n1 [color="#d00000"];
+  n0 -> n3;
+  n1 -> n2 [color="#d00000"];
+  n2 -> n0 [color="#d00000"];
+  n3 -> n3 [color="#d00000"];
+  n3 -> n4;
+}
+`, output.String())
+	})
+}
+
+func TestWriteMermaid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Graph", func(t *testing.T) {
+		t.Parallel()
+
+		var output strings.Builder
+
+		require.NoError(t, deps.WriteMermaid(&output, map[string][]string{
+			`say "hi"`: {"b"},
+			"b":        {},
+		}, deps.ExportOptions[string]{}))
+
+		require.Equal(t, `flowchart TD
+  n0["b"]
+  n1["say #quot;hi#quot;"]
+  n1 --> n0
+`, output.String())
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		t.Parallel()
+
+		var output strings.Builder
+
+		require.NoError(t, deps.WriteMermaid(&output, map[string][]string{
+			"a": {"b", "c"},
+			"b": {"a"},
+			"c": {},
+		}, deps.ExportOptions[string]{}))
+
+		require.Equal(t, `flowchart TD
+  n0["a"]
+  n1["b"]
+  n2["c"]
+  n0 --> n1
+  n0 --> n2
+  n1 --> n0
+  classDef cycle stroke:#d00000,stroke-width:2px
+  class n0,n1 cycle
+  linkStyle 0,2 stroke:#d00000
+`, output.String())
+	})
+}
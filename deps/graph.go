@@ -0,0 +1,219 @@
+package deps
+
+import (
+	"slices"
+)
+
+// Graph is a resolved dependency graph, where each module lists the modules it depends on.
+//
+// Modules are sorted by depth: a module without dependencies has a depth of 0, a module that only depends on
+// modules of depth 0 has a depth of 1, and so on. Modules of the same depth form a layer, and do not depend on each
+// other. The order of modules within a layer is unspecified.
+type Graph[Mod comparable] struct {
+	deps       map[Mod][]Mod
+	dependants map[Mod][]Mod
+	depths     map[Mod]int
+	layers     [][]Mod
+}
+
+// NewGraph resolves a dependency graph. Every module must be a key of the map, including modules without
+// dependencies.
+//
+// It returns a *CircularDependencyError if the graph contains cycles, and wraps ErrSelfDependency or
+// ErrUndeclaredDependency if a module depends on itself or on a module that is not declared.
+func NewGraph[Mod comparable](deps map[Mod][]Mod) (*Graph[Mod], error) {
+	graph := &Graph[Mod]{
+		deps:       make(map[Mod][]Mod, len(deps)),
+		dependants: make(map[Mod][]Mod, len(deps)),
+		depths:     make(map[Mod]int, len(deps)),
+	}
+
+	err := checkDeclarations(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	// https://dnaeon.github.io/dependency-graph-resolution-algorithm-in-go/
+	// Convert dependencies to a map. The algorithm performs better using maps behavior.
+	depsGraph := make(map[Mod]map[Mod]bool, len(deps))
+
+	for mod, localDeps := range deps {
+		depsGraph[mod] = make(map[Mod]bool, len(localDeps))
+
+		for _, dep := range localDeps {
+			if depsGraph[mod][dep] {
+				continue
+			}
+
+			depsGraph[mod][dep] = true
+			graph.deps[mod] = append(graph.deps[mod], dep)
+			graph.dependants[dep] = append(graph.dependants[dep], mod)
+		}
+	}
+
+	// To resolve every dependency, we triage the mods regarding their depths. We unwrap a single depth at a time,
+	// until the map of dependencies is empty.
+	for len(depsGraph) > 0 {
+		var layer []Mod
+
+		for mod, dependencies := range depsGraph {
+			if len(dependencies) == 0 {
+				layer = append(layer, mod)
+			}
+		}
+
+		// A given depth n+1 must resolve to at least one node of depth n (because each level of depth depends on
+		// the previous one). If we can't find any node of depth n, then we have a circular dependency.
+		if len(layer) == 0 {
+			return nil, &CircularDependencyError[Mod]{Cycles: findCycles(depsGraph)}
+		}
+
+		for _, mod := range layer {
+			// A resolved node can be removed from the original graph, and from the dependencies of other nodes.
+			delete(depsGraph, mod)
+
+			for _, dependantMod := range depsGraph {
+				delete(dependantMod, mod)
+			}
+
+			graph.depths[mod] = len(graph.layers)
+		}
+
+		graph.layers = append(graph.layers, layer)
+	}
+
+	return graph, nil
+}
+
+// Len returns the number of modules in the graph.
+func (graph *Graph[Mod]) Len() int {
+	return len(graph.depths)
+}
+
+// Has returns true if the module is part of the graph.
+func (graph *Graph[Mod]) Has(mod Mod) bool {
+	_, ok := graph.depths[mod]
+
+	return ok
+}
+
+// Depth returns the depth of a module, or false if the module is not part of the graph.
+func (graph *Graph[Mod]) Depth(mod Mod) (int, bool) {
+	depth, ok := graph.depths[mod]
+
+	return depth, ok
+}
+
+// Layers returns the modules grouped by depth, starting with the modules without dependencies. Modules of a layer
+// can be processed concurrently, once every previous layer is done.
+func (graph *Graph[Mod]) Layers() [][]Mod {
+	layers := make([][]Mod, len(graph.layers))
+	for i, layer := range graph.layers {
+		layers[i] = slices.Clone(layer)
+	}
+
+	return layers
+}
+
+// Order returns the modules in topological order: every module comes after its dependencies. Use it to start
+// modules, and iterate it backward to stop them.
+func (graph *Graph[Mod]) Order() []Mod {
+	return slices.Concat(graph.layers...)
+}
+
+// Dependencies returns the direct dependencies of a module, in the order they were declared. A dependency listed
+// more than once is only returned once.
+func (graph *Graph[Mod]) Dependencies(mod Mod) []Mod {
+	return slices.Clone(graph.deps[mod])
+}
+
+// TransitiveDependencies returns every module a module depends on, directly or not, in topological order.
+func (graph *Graph[Mod]) TransitiveDependencies(mod Mod) []Mod {
+	return graph.walk(mod, graph.deps)
+}
+
+// Dependants returns the modules that directly depend on a module.
+func (graph *Graph[Mod]) Dependants(mod Mod) []Mod {
+	return slices.Clone(graph.dependants[mod])
+}
+
+// TransitiveDependants returns every module that depends on a module, directly or not, in topological order.
+func (graph *Graph[Mod]) TransitiveDependants(mod Mod) []Mod {
+	return graph.walk(mod, graph.dependants)
+}
+
+// Subgraph returns the graph of the given modules and their transitive dependencies. Modules that are not part of
+// the graph are ignored.
+func (graph *Graph[Mod]) Subgraph(mods ...Mod) *Graph[Mod] {
+	included := make(map[Mod]bool)
+
+	for _, mod := range mods {
+		if !graph.Has(mod) {
+			continue
+		}
+
+		included[mod] = true
+
+		for _, dep := range graph.TransitiveDependencies(mod) {
+			included[dep] = true
+		}
+	}
+
+	subgraph := &Graph[Mod]{
+		deps:       make(map[Mod][]Mod, len(included)),
+		dependants: make(map[Mod][]Mod, len(included)),
+		depths:     make(map[Mod]int, len(included)),
+	}
+
+	// Dependencies of an included module are always included, so depths are preserved.
+	for _, layer := range graph.layers {
+		var subLayer []Mod
+
+		for _, mod := range layer {
+			if !included[mod] {
+				continue
+			}
+
+			subLayer = append(subLayer, mod)
+			subgraph.depths[mod] = len(subgraph.layers)
+			subgraph.deps[mod] = slices.Clone(graph.deps[mod])
+
+			for _, dep := range graph.deps[mod] {
+				subgraph.dependants[dep] = append(subgraph.dependants[dep], mod)
+			}
+		}
+
+		if len(subLayer) > 0 {
+			subgraph.layers = append(subgraph.layers, subLayer)
+		}
+	}
+
+	return subgraph
+}
+
+// walk returns every module reachable from mod through edges, in topological order.
+func (graph *Graph[Mod]) walk(mod Mod, edges map[Mod][]Mod) []Mod {
+	visited := map[Mod]bool{mod: true}
+	queue := slices.Clone(edges[mod])
+
+	var reached []Mod
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current] {
+			continue
+		}
+
+		visited[current] = true
+		reached = append(reached, current)
+		queue = append(queue, edges[current]...)
+	}
+
+	slices.SortStableFunc(reached, func(a, b Mod) int {
+		return graph.depths[a] - graph.depths[b]
+	})
+
+	return reached
+}
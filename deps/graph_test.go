@@ -0,0 +1,128 @@
+package deps_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+// graphTestDeps is the dependency graph of TestResolveDependants/Dependants.
+var graphTestDeps = map[string][]string{
+	"mod:1": {"mod:6"},
+	"mod:2": {},
+	"mod:3": {"mod:4"},
+	"mod:4": {"mod:1", "mod:2"},
+	"mod:5": {"mod:6"},
+	"mod:6": {},
+}
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	graph, err := deps.NewGraph(graphTestDeps)
+	require.NoError(t, err)
+
+	t.Run("Layers", func(t *testing.T) {
+		t.Parallel()
+
+		layers := graph.Layers()
+		require.Len(t, layers, 4)
+		require.ElementsMatch(t, []string{"mod:2", "mod:6"}, layers[0])
+		require.ElementsMatch(t, []string{"mod:1", "mod:5"}, layers[1])
+		require.Equal(t, []string{"mod:4"}, layers[2])
+		require.Equal(t, []string{"mod:3"}, layers[3])
+
+		depth, ok := graph.Depth("mod:4")
+		require.True(t, ok)
+		require.Equal(t, 2, depth)
+
+		_, ok = graph.Depth("mod:7")
+		require.False(t, ok)
+		require.Equal(t, 6, graph.Len())
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		t.Parallel()
+
+		order := graph.Order()
+		require.Len(t, order, 6)
+
+		positions := make(map[string]int, len(order))
+		for i, mod := range order {
+			positions[mod] = i
+		}
+
+		for mod, modDeps := range graphTestDeps {
+			for _, dep := range modDeps {
+				require.Less(t, positions[dep], positions[mod], "%s must come after %s", mod, dep)
+			}
+		}
+	})
+
+	t.Run("Dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, []string{"mod:1", "mod:2"}, graph.Dependencies("mod:4"))
+		require.Empty(t, graph.Dependencies("mod:6"))
+
+		transitive := graph.TransitiveDependencies("mod:3")
+		require.Len(t, transitive, 4)
+		require.ElementsMatch(t, []string{"mod:6", "mod:2"}, transitive[:2])
+		require.Equal(t, []string{"mod:1", "mod:4"}, transitive[2:])
+	})
+
+	t.Run("Dependants", func(t *testing.T) {
+		t.Parallel()
+
+		require.ElementsMatch(t, []string{"mod:1", "mod:5"}, graph.Dependants("mod:6"))
+		require.Empty(t, graph.Dependants("mod:3"))
+
+		transitive := graph.TransitiveDependants("mod:6")
+		require.Len(t, transitive, 4)
+		require.ElementsMatch(t, []string{"mod:1", "mod:5"}, transitive[:2])
+		require.Equal(t, []string{"mod:4", "mod:3"}, transitive[2:])
+	})
+
+	t.Run("Subgraph", func(t *testing.T) {
+		t.Parallel()
+
+		subgraph := graph.Subgraph("mod:4", "mod:7")
+		require.Equal(t, 4, subgraph.Len())
+		require.False(t, subgraph.Has("mod:3"))
+		require.False(t, subgraph.Has("mod:5"))
+		require.Equal(t, []string{"mod:4"}, subgraph.Dependants("mod:1"))
+		require.Empty(t, subgraph.Dependants("mod:4"))
+
+		layers := subgraph.Layers()
+		require.Len(t, layers, 3)
+		require.ElementsMatch(t, []string{"mod:2", "mod:6"}, layers[0])
+		require.Equal(t, []string{"mod:1"}, layers[1])
+		require.Equal(t, []string{"mod:4"}, layers[2])
+	})
+
+	t.Run("Circular", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := deps.NewGraph(map[string][]string{"mod:1": {"mod:2"}, "mod:2": {"mod:1"}, "mod:3": {}})
+		require.ErrorIs(t, err, deps.ErrCircularDependency)
+	})
+}
+
+func TestResolveDependantsAliasing(t *testing.T) {
+	t.Parallel()
+
+	// Extra capacity lets append write past the length of the original slice.
+	mod1Deps := make([]string, 1, 4)
+	mod1Deps[0] = "dep:1"
+
+	mods := map[string][]string{"mod:1": mod1Deps, "mod:2": {"dep:2"}}
+
+	resolved, err := deps.ResolveDependants(mods, map[string][]string{"mod:1": {"mod:2"}, "mod:2": {}})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"dep:1", "dep:2"}, resolved["mod:1"])
+	require.Equal(t, []string{"dep:1"}, mods["mod:1"])
+	require.Empty(t, mod1Deps[:2][1])
+}
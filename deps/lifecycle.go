@@ -0,0 +1,316 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout is the time given to each operation of a component, unless configured otherwise.
+const DefaultTimeout = 30 * time.Second
+
+var (
+	ErrDuplicateComponent = errors.New("duplicate component")
+	ErrAlreadyStarted     = errors.New("lifecycle already started")
+	ErrRunTimeout         = errors.New("run did not return after the components were stopped")
+)
+
+// Component is a part of a service that must be started before it is used, and stopped on shutdown.
+type Component struct {
+	// Name of the component, used to declare dependencies and in errors.
+	Name string
+	// DependsOn lists the names of the components that must be started before this one.
+	DependsOn []string
+	// Start the component. It is optional.
+	Start func(ctx context.Context) error
+	// Stop the component, and release its resources. It is optional.
+	Stop func(ctx context.Context) error
+	// Health reports an error if the component is not healthy. It is optional.
+	Health func(ctx context.Context) error
+	// Timeout of each call to Start, Stop and Health. The context passed to these functions is canceled once it is
+	// reached. Defaults to the timeout of the Lifecycle.
+	Timeout time.Duration
+}
+
+// LifecycleOptions configures a Lifecycle.
+type LifecycleOptions struct {
+	// Timeout of the components that do not set their own. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Signals that stop the components when received by Run. Defaults to os.Interrupt and syscall.SIGTERM.
+	Signals []os.Signal
+}
+
+// Lifecycle starts and stops the components of a service, in the order of their dependencies.
+//
+//	lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+//
+//	err := lifecycle.Register(
+//		&deps.Component{
+//			Name:  "otel",
+//			Start: func(context.Context) error { return otel.Init(otelConfig) },
+//			Stop:  func(context.Context) error { otelConfig.Flush(); return nil },
+//		},
+//		&deps.Component{
+//			Name:      "postgres",
+//			DependsOn: []string{"otel"},
+//			Start:     func(ctx context.Context) error { return postgres.Ping(ctx, db) },
+//			Stop:      func(context.Context) error { return db.Close() },
+//			Health:    db.PingContext,
+//		},
+//		&deps.Component{
+//			Name:      "server",
+//			DependsOn: []string{"postgres"},
+//			Stop:      server.Shutdown,
+//		},
+//	)
+//
+//	// Blocks until SIGTERM, then stops the server, postgres and otel, in this order. Serve returns once the server
+//	// is shut down.
+//	err = lifecycle.Run(ctx, func(context.Context) error {
+//		err := server.Serve(listener)
+//		if errors.Is(err, http.ErrServerClosed) {
+//			return nil
+//		}
+//
+//		return err
+//	})
+type Lifecycle struct {
+	options    LifecycleOptions
+	components map[string]*Component
+	names      []string
+
+	// started lists the started components, in the order they were started.
+	started []*Component
+	running bool
+	mu      sync.Mutex
+}
+
+// NewLifecycle returns an empty Lifecycle.
+func NewLifecycle(options LifecycleOptions) *Lifecycle {
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	if len(options.Signals) == 0 {
+		options.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	return &Lifecycle{
+		options:    options,
+		components: make(map[string]*Component),
+	}
+}
+
+// Register adds components to the Lifecycle. Dependencies are checked when the components are started, so
+// components can be registered in any order.
+func (lifecycle *Lifecycle) Register(components ...*Component) error {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+
+	if lifecycle.running {
+		return ErrAlreadyStarted
+	}
+
+	for _, component := range components {
+		if _, ok := lifecycle.components[component.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateComponent, component.Name)
+		}
+
+		lifecycle.components[component.Name] = component
+		lifecycle.names = append(lifecycle.names, component.Name)
+	}
+
+	return nil
+}
+
+// Start starts every component, after the components they depend on. If a component fails to start, the components
+// that were already started are stopped, in reverse order.
+func (lifecycle *Lifecycle) Start(ctx context.Context) error {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+
+	if lifecycle.running {
+		return ErrAlreadyStarted
+	}
+
+	order, err := lifecycle.order()
+	if err != nil {
+		return err
+	}
+
+	lifecycle.running = true
+
+	for _, component := range order {
+		err = lifecycle.call(ctx, component, component.Start)
+		if err != nil {
+			err = fmt.Errorf("start %s: %w", component.Name, err)
+
+			return errors.Join(err, lifecycle.stop(context.WithoutCancel(ctx)))
+		}
+
+		lifecycle.started = append(lifecycle.started, component)
+	}
+
+	return nil
+}
+
+// Stop stops the started components, in the reverse order they were started. Every component is stopped, even if
+// another one fails to stop, and the errors are joined.
+func (lifecycle *Lifecycle) Stop(ctx context.Context) error {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+
+	return lifecycle.stop(ctx)
+}
+
+// Health checks the started components, and joins the errors of those that are not healthy.
+func (lifecycle *Lifecycle) Health(ctx context.Context) error {
+	lifecycle.mu.Lock()
+	started := slices.Clone(lifecycle.started)
+	lifecycle.mu.Unlock()
+
+	var errs []error
+
+	for _, component := range started {
+		err := lifecycle.call(ctx, component, component.Health)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", component.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run starts the components, then calls run, if not nil, until it returns, the context is canceled, or one of the
+// configured signals is received. The components are then stopped, and every error is joined.
+//
+// run is called in its own goroutine, so the components are stopped on shutdown even if it ignores its context,
+// which is canceled. Run then waits up to the timeout of the Lifecycle for run to return, usually because one of the
+// stopped components served it, and reports ErrRunTimeout otherwise. Stopping the components uses a context that is
+// not canceled with the parent one, so components can shut down gracefully.
+func (lifecycle *Lifecycle) Run(ctx context.Context, run func(ctx context.Context) error) error {
+	signalCtx, cancel := signal.NotifyContext(ctx, lifecycle.options.Signals...)
+	defer cancel()
+
+	err := lifecycle.Start(signalCtx)
+	if err != nil {
+		return err
+	}
+
+	// Buffered, so the goroutine does not leak once run returns, if Run stopped waiting for it.
+	done := make(chan error, 1)
+
+	if run != nil {
+		go func() {
+			done <- run(signalCtx)
+		}()
+	}
+
+	var (
+		runErr   error
+		returned bool
+		timedOut bool
+	)
+
+	select {
+	case runErr = <-done:
+		returned = true
+	case <-signalCtx.Done():
+	}
+
+	// Restore the default behavior of the signals, so a second signal kills the process if stopping hangs.
+	cancel()
+
+	stopErr := lifecycle.Stop(context.WithoutCancel(ctx))
+
+	if run != nil && !returned {
+		select {
+		case runErr = <-done:
+		case <-time.After(lifecycle.options.Timeout):
+			timedOut = true
+		}
+	}
+
+	switch {
+	case timedOut:
+		runErr = fmt.Errorf("%w: waited %s", ErrRunTimeout, lifecycle.options.Timeout)
+	case runErr != nil:
+		runErr = fmt.Errorf("run: %w", runErr)
+	}
+
+	return errors.Join(runErr, stopErr)
+}
+
+// order returns the components in the order they must be started.
+func (lifecycle *Lifecycle) order() ([]*Component, error) {
+	dependencies := make(map[string][]string, len(lifecycle.components))
+	for name, component := range lifecycle.components {
+		dependencies[name] = component.DependsOn
+	}
+
+	graph, err := NewGraph(dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("resolve components: %w", err)
+	}
+
+	// Start components of the same depth in registration order, so the sequence is reproducible.
+	position := make(map[string]int, len(lifecycle.names))
+	for i, name := range lifecycle.names {
+		position[name] = i
+	}
+
+	order := make([]*Component, 0, graph.Len())
+
+	for _, layer := range graph.Layers() {
+		slices.SortFunc(layer, func(a, b string) int {
+			return position[a] - position[b]
+		})
+
+		for _, name := range layer {
+			order = append(order, lifecycle.components[name])
+		}
+	}
+
+	return order, nil
+}
+
+func (lifecycle *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+
+	for _, component := range slices.Backward(lifecycle.started) {
+		err := lifecycle.call(ctx, component, component.Stop)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, err))
+		}
+	}
+
+	lifecycle.started = nil
+	lifecycle.running = false
+
+	return errors.Join(errs...)
+}
+
+// call runs an operation of a component with its timeout.
+func (lifecycle *Lifecycle) call(
+	ctx context.Context, component *Component, operation func(ctx context.Context) error,
+) error {
+	if operation == nil {
+		return nil
+	}
+
+	timeout := component.Timeout
+	if timeout == 0 {
+		timeout = lifecycle.options.Timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return operation(ctx)
+}
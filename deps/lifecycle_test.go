@@ -0,0 +1,188 @@
+package deps_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+var errComponent = errors.New("component error")
+
+type lifecycleRecorder struct {
+	calls []string
+	mu    sync.Mutex
+}
+
+func (recorder *lifecycleRecorder) component(name string, dependsOn ...string) *deps.Component {
+	record := func(operation string) func(ctx context.Context) error {
+		return func(context.Context) error {
+			recorder.mu.Lock()
+			defer recorder.mu.Unlock()
+
+			recorder.calls = append(recorder.calls, operation+" "+name)
+
+			return nil
+		}
+	}
+
+	return &deps.Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start:     record("start"),
+		Stop:      record("stop"),
+		Health:    record("health"),
+	}
+}
+
+func (recorder *lifecycleRecorder) Calls() []string {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return recorder.calls
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StartStop", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		require.NoError(t, lifecycle.Register(
+			recorder.component("server", "postgres", "smtp"),
+			recorder.component("postgres", "otel"),
+			recorder.component("smtp", "otel"),
+			recorder.component("otel"),
+		))
+
+		require.NoError(t, lifecycle.Start(t.Context()))
+		require.ErrorIs(t, lifecycle.Start(t.Context()), deps.ErrAlreadyStarted)
+		require.NoError(t, lifecycle.Health(t.Context()))
+		require.NoError(t, lifecycle.Stop(t.Context()))
+
+		require.Equal(t, []string{
+			"start otel", "start postgres", "start smtp", "start server",
+			"health otel", "health postgres", "health smtp", "health server",
+			"stop server", "stop smtp", "stop postgres", "stop otel",
+		}, recorder.Calls())
+	})
+
+	t.Run("StartFailure", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		failing := recorder.component("postgres", "otel")
+		failing.Start = func(context.Context) error { return errComponent }
+
+		require.NoError(t, lifecycle.Register(
+			recorder.component("otel"),
+			recorder.component("smtp"),
+			failing,
+			recorder.component("server", "postgres"),
+		))
+
+		err := lifecycle.Start(t.Context())
+		require.ErrorIs(t, err, errComponent)
+		require.ErrorContains(t, err, "start postgres")
+
+		require.Equal(t, []string{"start otel", "start smtp", "stop smtp", "stop otel"}, recorder.Calls())
+	})
+
+	t.Run("StopErrors", func(t *testing.T) {
+		t.Parallel()
+
+		errOther := errors.New("other error")
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		first := recorder.component("otel")
+		first.Stop = func(context.Context) error { return errComponent }
+		second := recorder.component("postgres", "otel")
+		second.Stop = func(context.Context) error { return errOther }
+		second.Health = func(context.Context) error { return errOther }
+
+		require.NoError(t, lifecycle.Register(first, second, recorder.component("server", "postgres")))
+		require.NoError(t, lifecycle.Start(t.Context()))
+
+		err := lifecycle.Health(t.Context())
+		require.ErrorIs(t, err, errOther)
+		require.ErrorContains(t, err, "postgres")
+
+		err = lifecycle.Stop(t.Context())
+		require.ErrorIs(t, err, errComponent)
+		require.ErrorIs(t, err, errOther)
+		require.Contains(t, recorder.Calls(), "stop server")
+
+		// Components are only stopped once.
+		require.NoError(t, lifecycle.Stop(t.Context()))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{Timeout: time.Hour})
+
+		require.NoError(t, lifecycle.Register(&deps.Component{
+			Name:    "slow",
+			Timeout: 10 * time.Millisecond,
+			Start: func(ctx context.Context) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+		}))
+
+		require.ErrorIs(t, lifecycle.Start(t.Context()), context.DeadlineExceeded)
+	})
+
+	t.Run("InvalidDependencies", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		require.NoError(t, lifecycle.Register(recorder.component("postgres", "otel")))
+		require.ErrorIs(t, lifecycle.Register(recorder.component("postgres")), deps.ErrDuplicateComponent)
+		require.ErrorIs(t, lifecycle.Start(t.Context()), deps.ErrUndeclaredDependency)
+		require.Empty(t, recorder.Calls())
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		require.NoError(t, lifecycle.Register(recorder.component("otel"), recorder.component("server", "otel")))
+
+		err := lifecycle.Run(t.Context(), func(context.Context) error { return errComponent })
+		require.ErrorIs(t, err, errComponent)
+
+		require.Equal(t, []string{"start otel", "start server", "stop server", "stop otel"}, recorder.Calls())
+	})
+
+	t.Run("RunCanceled", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{})
+
+		require.NoError(t, lifecycle.Register(recorder.component("otel")))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		require.NoError(t, lifecycle.Run(ctx, nil))
+		require.Equal(t, []string{"start otel", "stop otel"}, recorder.Calls())
+	})
+}
@@ -0,0 +1,85 @@
+//go:build unix
+
+package deps_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestLifecycleSignal(t *testing.T) {
+	t.Parallel()
+
+	// Each test listens to its own signal, so they do not stop each other.
+	t.Run("RunIgnoresContext", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{Signals: []os.Signal{syscall.SIGUSR1}})
+
+		// Like http.Server.Serve, run ignores its context, and only returns once the server is stopped.
+		started, stopped := make(chan struct{}), make(chan struct{})
+
+		server := recorder.component("server")
+		stop := server.Stop
+		server.Stop = func(ctx context.Context) error {
+			close(stopped)
+
+			return stop(ctx)
+		}
+
+		require.NoError(t, lifecycle.Register(server))
+
+		go func() {
+			<-started
+
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+		}()
+
+		err := lifecycle.Run(t.Context(), func(context.Context) error {
+			close(started)
+			<-stopped
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"start server", "stop server"}, recorder.Calls())
+	})
+
+	t.Run("RunTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := new(lifecycleRecorder)
+		lifecycle := deps.NewLifecycle(deps.LifecycleOptions{
+			Timeout: 50 * time.Millisecond,
+			Signals: []os.Signal{syscall.SIGUSR2},
+		})
+
+		require.NoError(t, lifecycle.Register(recorder.component("server")))
+
+		started, release := make(chan struct{}), make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		go func() {
+			<-started
+
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+		}()
+
+		err := lifecycle.Run(t.Context(), func(context.Context) error {
+			close(started)
+			<-release
+
+			return nil
+		})
+		require.ErrorIs(t, err, deps.ErrRunTimeout)
+		require.Equal(t, []string{"start server", "stop server"}, recorder.Calls())
+	})
+}
@@ -0,0 +1,279 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var ErrUnknownModule = errors.New("unknown module")
+
+// Resolver maintains the resolved dependencies of a graph that changes at runtime. Each change only resolves again
+// the modules it affects, instead of the whole graph.
+//
+//	resolver, err := deps.NewResolver(permissions, roles, deps.Options[string]{})
+//
+//	err = resolver.AddDependency("admin", "auditor")
+//
+//	permissions := resolver.Resolved("admin")
+//
+// A Resolver is safe for concurrent use.
+type Resolver[Mod comparable, Deps any] struct {
+	options Options[Deps]
+
+	mods       map[Mod][]Deps
+	deps       map[Mod][]Mod
+	dependants map[Mod][]Mod
+	resolved   map[Mod][]Deps
+
+	mu sync.RWMutex
+}
+
+// NewResolver resolves the dependencies of a graph, like ResolveDependantsWithOptions. Every module must be a key of
+// deps, including modules without dependencies.
+func NewResolver[Mod comparable, Deps any](
+	mods map[Mod][]Deps, deps map[Mod][]Mod, options Options[Deps],
+) (*Resolver[Mod, Deps], error) {
+	graph, err := NewGraph(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &Resolver[Mod, Deps]{
+		options:    options,
+		mods:       make(map[Mod][]Deps, len(deps)),
+		deps:       make(map[Mod][]Mod, len(deps)),
+		dependants: make(map[Mod][]Mod, len(deps)),
+		resolved:   make(map[Mod][]Deps, len(deps)),
+	}
+
+	for _, mod := range graph.Order() {
+		// Keep repeated dependencies, so they are inherited as many times as by ResolveDependantsWithOptions.
+		resolver.mods[mod] = slices.Clone(mods[mod])
+		resolver.deps[mod] = slices.Clone(deps[mod])
+
+		for _, dep := range deps[mod] {
+			resolver.dependants[dep] = append(resolver.dependants[dep], mod)
+		}
+
+		resolver.resolve(mod)
+	}
+
+	return resolver, nil
+}
+
+// Resolved returns the resolved dependencies of a module, or nil if the module is unknown.
+func (resolver *Resolver[Mod, Deps]) Resolved(mod Mod) []Deps {
+	resolver.mu.RLock()
+	defer resolver.mu.RUnlock()
+
+	return slices.Clone(resolver.resolved[mod])
+}
+
+// All returns the resolved dependencies of every module.
+func (resolver *Resolver[Mod, Deps]) All() map[Mod][]Deps {
+	resolver.mu.RLock()
+	defer resolver.mu.RUnlock()
+
+	all := make(map[Mod][]Deps, len(resolver.resolved))
+	for mod, resolved := range resolver.resolved {
+		all[mod] = slices.Clone(resolved)
+	}
+
+	return all
+}
+
+// SetModule adds a module, or replaces the dependencies of an existing one.
+func (resolver *Resolver[Mod, Deps]) SetModule(mod Mod, deps []Deps) {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	if _, ok := resolver.deps[mod]; !ok {
+		resolver.deps[mod] = nil
+	}
+
+	resolver.mods[mod] = slices.Clone(deps)
+	resolver.update(mod)
+}
+
+// RemoveModule removes a module. The modules that depended on it lose the dependencies they inherited from it.
+func (resolver *Resolver[Mod, Deps]) RemoveModule(mod Mod) error {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	if _, ok := resolver.deps[mod]; !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownModule, mod)
+	}
+
+	for _, dep := range resolver.deps[mod] {
+		resolver.dependants[dep] = deleteMod(resolver.dependants[dep], mod)
+	}
+
+	dependants := resolver.dependants[mod]
+	for _, dependant := range dependants {
+		resolver.deps[dependant] = deleteMod(resolver.deps[dependant], mod)
+	}
+
+	delete(resolver.mods, mod)
+	delete(resolver.deps, mod)
+	delete(resolver.dependants, mod)
+	delete(resolver.resolved, mod)
+
+	resolver.update(dependants...)
+
+	return nil
+}
+
+// AddDependency makes mod depend on dep. Both modules must exist, and the dependency must not create a cycle.
+func (resolver *Resolver[Mod, Deps]) AddDependency(mod, dep Mod) error {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	for _, name := range []Mod{mod, dep} {
+		if _, ok := resolver.deps[name]; !ok {
+			return fmt.Errorf("%w: %v", ErrUnknownModule, name)
+		}
+	}
+
+	if mod == dep {
+		return fmt.Errorf("%w: %v", ErrSelfDependency, mod)
+	}
+
+	if slices.Contains(resolver.deps[mod], dep) {
+		return nil
+	}
+
+	// The new dependency creates a cycle if dep already depends on mod.
+	path := resolver.path(dep, mod)
+	if path != nil {
+		return &CircularDependencyError[Mod]{Cycles: [][]Mod{rotateCycle(path)}}
+	}
+
+	resolver.deps[mod] = append(resolver.deps[mod], dep)
+	resolver.dependants[dep] = append(resolver.dependants[dep], mod)
+	resolver.update(mod)
+
+	return nil
+}
+
+// RemoveDependency removes the dependency of mod on dep, if any.
+func (resolver *Resolver[Mod, Deps]) RemoveDependency(mod, dep Mod) error {
+	resolver.mu.Lock()
+	defer resolver.mu.Unlock()
+
+	if _, ok := resolver.deps[mod]; !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownModule, mod)
+	}
+
+	if !slices.Contains(resolver.deps[mod], dep) {
+		return nil
+	}
+
+	resolver.deps[mod] = deleteMod(resolver.deps[mod], dep)
+	resolver.dependants[dep] = deleteMod(resolver.dependants[dep], mod)
+	resolver.update(mod)
+
+	return nil
+}
+
+// update resolves the given modules again, along with their transitive dependants, after their dependencies.
+func (resolver *Resolver[Mod, Deps]) update(mods ...Mod) {
+	affected := make(map[Mod]bool)
+	queue := slices.Clone(mods)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if affected[current] {
+			continue
+		}
+
+		affected[current] = true
+		queue = append(queue, resolver.dependants[current]...)
+	}
+
+	// Count the affected dependencies of each affected module, to resolve them in topological order.
+	pending := make(map[Mod]int, len(affected))
+
+	for mod := range affected {
+		for _, dep := range resolver.deps[mod] {
+			if affected[dep] {
+				pending[mod]++
+			}
+		}
+	}
+
+	for mod := range affected {
+		if pending[mod] == 0 {
+			queue = append(queue, mod)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		resolver.resolve(current)
+
+		for _, dependant := range resolver.dependants[current] {
+			pending[dependant]--
+			if pending[dependant] == 0 {
+				queue = append(queue, dependant)
+			}
+		}
+	}
+}
+
+// resolve computes the dependencies of a module, once its own dependencies are resolved.
+func (resolver *Resolver[Mod, Deps]) resolve(mod Mod) {
+	resolved := slices.Clone(resolver.mods[mod])
+	for _, dep := range resolver.deps[mod] {
+		resolved = append(resolved, resolver.resolved[dep]...)
+	}
+
+	resolver.resolved[mod] = resolver.options.apply(resolved)
+}
+
+// path returns the shortest chain of dependencies from one module to another, or nil if there is none.
+func (resolver *Resolver[Mod, Deps]) path(from, to Mod) []Mod {
+	parents := map[Mod]Mod{from: from}
+	queue := []Mod{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			path := []Mod{current}
+			for path[0] != from {
+				path = append([]Mod{parents[path[0]]}, path...)
+			}
+
+			return path
+		}
+
+		for _, dep := range resolver.deps[current] {
+			if _, visited := parents[dep]; !visited {
+				parents[dep] = current
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rotateCycle rotates a cycle so it starts with the module whose formatted name sorts first, like findCycles.
+func rotateCycle[Mod comparable](cycle []Mod) []Mod {
+	start := slices.Index(cycle, slices.MinFunc(cycle, compareFormatted[Mod]))
+
+	return slices.Concat(cycle[start:], cycle[:start])
+}
+
+func deleteMod[Mod comparable](mods []Mod, mod Mod) []Mod {
+	return slices.DeleteFunc(mods, func(current Mod) bool {
+		return current == mod
+	})
+}
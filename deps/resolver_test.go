@@ -0,0 +1,208 @@
+package deps_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/deps"
+)
+
+func TestResolver(t *testing.T) {
+	t.Parallel()
+
+	newResolver := func(t *testing.T) *deps.Resolver[string, string] {
+		t.Helper()
+
+		resolver, err := deps.NewResolver(map[string][]string{
+			"admin":  {"delete"},
+			"editor": {"write"},
+			"reader": {"read"},
+		}, map[string][]string{
+			"admin":  {"editor"},
+			"editor": {"reader"},
+			"reader": {},
+		}, deps.Options[string]{Key: func(dep string) any { return dep }})
+		require.NoError(t, err)
+
+		return resolver
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := newResolver(t)
+
+		require.Equal(t, map[string][]string{
+			"admin":  {"delete", "write", "read"},
+			"editor": {"write", "read"},
+			"reader": {"read"},
+		}, resolver.All())
+		require.Nil(t, resolver.Resolved("guest"))
+	})
+
+	t.Run("SetModule", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := newResolver(t)
+
+		resolver.SetModule("reader", []string{"read", "list"})
+		resolver.SetModule("auditor", []string{"audit", "read"})
+		require.NoError(t, resolver.AddDependency("admin", "auditor"))
+
+		require.Equal(t, map[string][]string{
+			"admin":   {"delete", "write", "read", "list", "audit"},
+			"auditor": {"audit", "read"},
+			"editor":  {"write", "read", "list"},
+			"reader":  {"read", "list"},
+		}, resolver.All())
+	})
+
+	t.Run("RemoveModule", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := newResolver(t)
+
+		require.NoError(t, resolver.RemoveModule("editor"))
+		require.ErrorIs(t, resolver.RemoveModule("editor"), deps.ErrUnknownModule)
+
+		require.Equal(t, map[string][]string{
+			"admin":  {"delete"},
+			"reader": {"read"},
+		}, resolver.All())
+
+		// The edge to the removed module is gone, so it can be added back without affecting admin.
+		resolver.SetModule("editor", []string{"write"})
+		require.Equal(t, []string{"delete"}, resolver.Resolved("admin"))
+	})
+
+	t.Run("RemoveDependency", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := newResolver(t)
+
+		require.NoError(t, resolver.RemoveDependency("editor", "reader"))
+		require.NoError(t, resolver.RemoveDependency("editor", "reader"))
+		require.ErrorIs(t, resolver.RemoveDependency("guest", "reader"), deps.ErrUnknownModule)
+
+		require.Equal(t, []string{"delete", "write"}, resolver.Resolved("admin"))
+		require.Equal(t, []string{"write"}, resolver.Resolved("editor"))
+	})
+
+	t.Run("AddDependency/Errors", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := newResolver(t)
+
+		require.ErrorIs(t, resolver.AddDependency("guest", "reader"), deps.ErrUnknownModule)
+		require.ErrorIs(t, resolver.AddDependency("reader", "guest"), deps.ErrUnknownModule)
+		require.ErrorIs(t, resolver.AddDependency("reader", "reader"), deps.ErrSelfDependency)
+
+		err := resolver.AddDependency("reader", "admin")
+		require.ErrorIs(t, err, deps.ErrCircularDependency)
+
+		var cycleErr *deps.CircularDependencyError[string]
+		require.ErrorAs(t, err, &cycleErr)
+		require.Equal(t, [][]string{{"admin", "editor", "reader"}}, cycleErr.Cycles)
+
+		// Rejected changes leave the graph untouched.
+		require.Equal(t, []string{"read"}, resolver.Resolved("reader"))
+		require.NoError(t, resolver.AddDependency("admin", "reader"))
+	})
+
+	t.Run("RepeatedDependency", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, err := deps.NewResolver(map[string][]string{
+			"editor": {"write"},
+			"reader": {"read"},
+		}, map[string][]string{
+			"editor": {"reader", "reader"},
+			"reader": {},
+		}, deps.Options[string]{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"write", "read", "read"}, resolver.Resolved("editor"))
+
+		resolver.SetModule("reader", []string{"list"})
+		require.Equal(t, []string{"write", "list", "list"}, resolver.Resolved("editor"))
+
+		require.NoError(t, resolver.RemoveDependency("editor", "reader"))
+		require.Equal(t, []string{"write"}, resolver.Resolved("editor"))
+	})
+
+	t.Run("MatchesFullResolution", func(t *testing.T) {
+		t.Parallel()
+
+		mods, modDeps := benchmarkGraph(50, 5)
+		options := deps.Options[string]{Key: func(dep string) any { return dep }}
+
+		resolver, err := deps.NewResolver(mods, modDeps, options)
+		require.NoError(t, err)
+
+		resolver.SetModule("mod:10", []string{"dep:new"})
+		mods["mod:10"] = []string{"dep:new"}
+
+		require.NoError(t, resolver.RemoveDependency("mod:25", "mod:20"))
+		modDeps["mod:25"] = deleteString(modDeps["mod:25"], "mod:20")
+
+		expect, err := deps.ResolveDependantsWithOptions(mods, modDeps, options)
+		require.NoError(t, err)
+		require.Equal(t, expect, resolver.All())
+	})
+}
+
+// benchmarkGraph returns a graph where each module depends on up to width modules declared before it.
+func benchmarkGraph(size, width int) (map[string][]string, map[string][]string) {
+	mods := make(map[string][]string, size)
+	modDeps := make(map[string][]string, size)
+
+	for i := range size {
+		mod := fmt.Sprintf("mod:%d", i)
+		mods[mod] = []string{fmt.Sprintf("dep:%d", i)}
+		modDeps[mod] = []string{}
+
+		for j := max(0, i-width); j < i; j++ {
+			modDeps[mod] = append(modDeps[mod], fmt.Sprintf("mod:%d", j))
+		}
+	}
+
+	return mods, modDeps
+}
+
+func deleteString(values []string, value string) []string {
+	var output []string
+
+	for _, current := range values {
+		if current != value {
+			output = append(output, current)
+		}
+	}
+
+	return output
+}
+
+func BenchmarkResolver(b *testing.B) {
+	mods, modDeps := benchmarkGraph(1000, 3)
+	options := deps.Options[string]{Key: func(dep string) any { return dep }, Compare: strings.Compare}
+
+	// Updating a module near the end of the graph only affects a few dependants.
+	b.Run("Incremental", func(b *testing.B) {
+		resolver, err := deps.NewResolver(mods, modDeps, options)
+		require.NoError(b, err)
+
+		for b.Loop() {
+			resolver.SetModule("mod:990", []string{"dep:990", "dep:new"})
+		}
+	})
+
+	b.Run("Full", func(b *testing.B) {
+		for b.Loop() {
+			mods["mod:990"] = []string{"dep:990", "dep:new"}
+
+			_, err := deps.ResolveDependantsWithOptions(mods, modDeps, options)
+			require.NoError(b, err)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -23,6 +24,13 @@ type Config interface {
 	RpcInterceptor() grpc.ServerOption
 }
 
+// MeterConfig is implemented by the configurations that export metrics. It is optional, so Init only sets the meter
+// provider of the configurations that implement it.
+type MeterConfig interface {
+	// GetMeterProvider returns the provider of the metrics. A nil provider leaves metrics disabled.
+	GetMeterProvider() (metric.MeterProvider, error)
+}
+
 func Init(config Config) error {
 	// Telemetry disabled.
 	if config == nil {
@@ -49,9 +57,23 @@ func Init(config Config) error {
 		return fmt.Errorf("get logger: %w", err)
 	}
 
+	var meterProvider metric.MeterProvider
+
+	meterConfig, ok := config.(MeterConfig)
+	if ok {
+		meterProvider, err = meterConfig.GetMeterProvider()
+		if err != nil {
+			return fmt.Errorf("get meter provider: %w", err)
+		}
+	}
+
 	otel.SetTextMapPropagator(tracePropagator)
 	otel.SetTracerProvider(traceProvider)
 	global.SetLoggerProvider(logger)
 
+	if meterProvider != nil {
+		otel.SetMeterProvider(meterProvider)
+	}
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"time"
 
+	mexporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/metric"
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	gcppropagator "github.com/GoogleCloudPlatform/opentelemetry-operations-go/propagator"
 	"github.com/fatih/color"
@@ -18,6 +19,7 @@ import (
 	otellib "go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -26,11 +28,18 @@ import (
 	"github.com/a-novel-kit/golib/otel"
 )
 
-var _ otel.Config = (*Gcloud)(nil)
+var (
+	_ otel.Config      = (*Gcloud)(nil)
+	_ otel.MeterConfig = (*Gcloud)(nil)
+)
 
 type Gcloud struct {
-	ProjectID    string        `json:"projectID"    yaml:"projectID"`
-	FlushTimeout time.Duration `json:"flushTimeout" yaml:"flushTimeout"`
+	ProjectID    string        `env:"PROJECT_ID"    json:"projectID"    yaml:"projectID"`
+	FlushTimeout time.Duration `env:"FLUSH_TIMEOUT" json:"flushTimeout" yaml:"flushTimeout"`
+	// Metrics enables the export of metrics. Disabled by default.
+	Metrics bool `env:"METRICS" json:"metrics" yaml:"metrics"`
+	// MetricInterval is the time between two exports of metrics. Defaults to one minute.
+	MetricInterval time.Duration `env:"METRIC_INTERVAL" json:"metricInterval" yaml:"metricInterval"`
 }
 
 func (config *Gcloud) Init() error {
@@ -83,7 +92,26 @@ func (config *Gcloud) GetLogger() (log.LoggerProvider, error) {
 	), nil
 }
 
-// Flush shuts down tracer and logger providers.
+// GetMeterProvider exports metrics to Cloud Monitoring, if enabled.
+func (config *Gcloud) GetMeterProvider() (metric.MeterProvider, error) {
+	if !config.Metrics {
+		return nil, nil
+	}
+
+	var opts []mexporter.Option
+	if config.ProjectID != "" {
+		opts = append(opts, mexporter.WithProjectID(config.ProjectID))
+	}
+
+	exporter, err := mexporter.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP metric exporter: %w", err)
+	}
+
+	return newMeterProvider(exporter, config.MetricInterval), nil
+}
+
+// Flush shuts down tracer and meter providers.
 func (config *Gcloud) Flush() {
 	provider := otellib.GetTracerProvider()
 
@@ -94,6 +122,10 @@ func (config *Gcloud) Flush() {
 			stdlog.Fatalf("Failed to shutdown tracer provider: %v\n", err)
 		}
 	}
+
+	if config.Metrics {
+		shutdownMeterProvider()
+	}
 }
 
 // HttpHandler is kept for interface compatibility but doesn't wrap anything in GCP mode.
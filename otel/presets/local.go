@@ -13,8 +13,10 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	otellib "go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -23,11 +25,18 @@ import (
 	"github.com/a-novel-kit/golib/otel"
 )
 
-var _ otel.Config = (*Local)(nil)
+var (
+	_ otel.Config      = (*Local)(nil)
+	_ otel.MeterConfig = (*Local)(nil)
+)
 
-// Local configures OTEL to log traces & logs to stdout.
+// Local configures OTEL to log traces & logs to stdout, and optionally metrics.
 type Local struct {
-	FlushTimeout time.Duration `json:"flushTimeout" yaml:"flushTimeout"`
+	FlushTimeout time.Duration `env:"FLUSH_TIMEOUT" json:"flushTimeout" yaml:"flushTimeout"`
+	// Metrics enables the export of metrics. Disabled by default.
+	Metrics bool `env:"METRICS" json:"metrics" yaml:"metrics"`
+	// MetricInterval is the time between two exports of metrics. Defaults to one minute.
+	MetricInterval time.Duration `env:"METRIC_INTERVAL" json:"metricInterval" yaml:"metricInterval"`
 }
 
 // Init just prints a banner for local dev mode.
@@ -73,6 +82,22 @@ func (config *Local) GetLogger() (log.LoggerProvider, error) {
 	), nil
 }
 
+func (config *Local) GetMeterProvider() (metric.MeterProvider, error) {
+	if !config.Metrics {
+		return nil, nil
+	}
+
+	metricExporter, err := stdoutmetric.New(
+		stdoutmetric.WithPrettyPrint(),
+		stdoutmetric.WithWriter(color.Output),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMeterProvider(metricExporter, config.MetricInterval), nil
+}
+
 func (config *Local) Flush() {
 	provider := otellib.GetTracerProvider()
 
@@ -83,6 +108,10 @@ func (config *Local) Flush() {
 			stdlog.Fatalf("Failed to shutdown tracer provider: %v\n", err)
 		}
 	}
+
+	if config.Metrics {
+		shutdownMeterProvider()
+	}
 }
 
 func (config *Local) HttpHandler() func(http.Handler) http.Handler {
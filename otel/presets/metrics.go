@@ -0,0 +1,30 @@
+package otelpresets
+
+import (
+	"context"
+	stdlog "log"
+	"time"
+
+	otellib "go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newMeterProvider returns a meter provider that exports metrics at the given interval. A zero interval uses the
+// default of the SDK, which is one minute.
+func newMeterProvider(exporter sdkmetric.Exporter, interval time.Duration) *sdkmetric.MeterProvider {
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+	)
+}
+
+// shutdownMeterProvider exports the pending metrics, and stops the meter provider set by otel.Init. Errors are only
+// logged, so the other providers are still flushed.
+func shutdownMeterProvider() {
+	mp, ok := otellib.GetMeterProvider().(*sdkmetric.MeterProvider)
+	if ok {
+		err := mp.Shutdown(context.Background())
+		if err != nil {
+			stdlog.Printf("Failed to shutdown meter provider: %v\n", err)
+		}
+	}
+}
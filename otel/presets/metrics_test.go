@@ -0,0 +1,45 @@
+package otelpresets_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/a-novel-kit/golib/otel"
+	otelpresets "github.com/a-novel-kit/golib/otel/presets"
+)
+
+func TestGetMeterProvider(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		config otel.MeterConfig
+	}{
+		{name: "Local", config: &otelpresets.Local{}},
+		{name: "Gcloud", config: &otelpresets.Gcloud{}},
+		{name: "Sentry", config: &otelpresets.Sentry{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Metrics are not exported unless enabled.
+			provider, err := testCase.config.GetMeterProvider()
+			require.NoError(t, err)
+			require.Nil(t, provider)
+		})
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := (&otelpresets.Local{Metrics: true}).GetMeterProvider()
+		require.NoError(t, err)
+		require.IsType(t, &sdkmetric.MeterProvider{}, provider)
+		require.NoError(t, provider.(*sdkmetric.MeterProvider).Shutdown(t.Context()))
+	})
+}
@@ -1,6 +1,7 @@
 package otelpresets
 
 import (
+	"errors"
 	"net/http"
 	"os"
 	"time"
@@ -12,7 +13,9 @@ import (
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -21,18 +24,47 @@ import (
 	"github.com/a-novel-kit/golib/otel"
 )
 
-var _ otel.Config = (*Sentry)(nil)
+var (
+	_ otel.Config      = (*Sentry)(nil)
+	_ otel.MeterConfig = (*Sentry)(nil)
+)
+
+var ErrInvalidSentryDSN = errors.New("invalid sentry DSN")
 
 type Sentry struct {
-	DSN          string        `json:"dsn"          yaml:"dsn"`
-	ServerName   string        `json:"serverName"   yaml:"serverName"`
-	Release      string        `json:"release"      yaml:"release"`
-	Environment  string        `json:"environment"  yaml:"environment"`
-	FlushTimeout time.Duration `json:"flushTimeout" yaml:"flushTimeout"`
-	Debug        bool          `json:"debug"        yaml:"debug"`
+	DSN          string        `env:"DSN"           json:"dsn"          secret:"true"       yaml:"dsn"`
+	ServerName   string        `env:"SERVER_NAME"   json:"serverName"   yaml:"serverName"`
+	Release      string        `env:"RELEASE"       json:"release"      yaml:"release"`
+	Environment  string        `env:"ENVIRONMENT"   json:"environment"  yaml:"environment"`
+	FlushTimeout time.Duration `env:"FLUSH_TIMEOUT" json:"flushTimeout" yaml:"flushTimeout"`
+	Debug        bool          `env:"DEBUG"         json:"debug"        yaml:"debug"`
+	// Metrics enables the export of metrics. Disabled by default.
+	Metrics bool `env:"METRICS" json:"metrics" yaml:"metrics"`
+	// MetricInterval is the time between two exports of metrics. Defaults to one minute.
+	MetricInterval time.Duration `env:"METRIC_INTERVAL" json:"metricInterval" yaml:"metricInterval"`
+}
+
+// Validate checks the DSN is well-formed. An empty DSN is accepted, and disables Sentry. The parse error is not
+// returned, as it contains the key of the DSN.
+func (config *Sentry) Validate() error {
+	if config.DSN == "" {
+		return nil
+	}
+
+	_, err := sentry.NewDsn(config.DSN)
+	if err != nil {
+		return ErrInvalidSentryDSN
+	}
+
+	return nil
 }
 
 func (config *Sentry) Init() error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
 	return sentry.Init(sentry.ClientOptions{
 		Dsn:              config.DSN,
 		EnableTracing:    true,
@@ -78,8 +110,26 @@ func (config *Sentry) GetLogger() (log.LoggerProvider, error) {
 	), nil
 }
 
+// GetMeterProvider writes metrics to stdout if enabled, like logs, as Sentry does not ingest OpenTelemetry metrics.
+func (config *Sentry) GetMeterProvider() (metric.MeterProvider, error) {
+	if !config.Metrics {
+		return nil, nil
+	}
+
+	metricExporter, err := stdoutmetric.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return newMeterProvider(metricExporter, config.MetricInterval), nil
+}
+
 func (config *Sentry) Flush() {
 	sentry.Flush(config.FlushTimeout)
+
+	if config.Metrics {
+		shutdownMeterProvider()
+	}
 }
 
 func (config *Sentry) HttpHandler() func(http.Handler) http.Handler {
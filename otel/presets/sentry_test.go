@@ -0,0 +1,30 @@
+package otelpresets_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/golib/config"
+	otelpresets "github.com/a-novel-kit/golib/otel/presets"
+)
+
+func TestSentryValidate(t *testing.T) {
+	t.Parallel()
+
+	// The port is invalid, so the DSN cannot be parsed.
+	const dsn = "https://secret-key@o1.ingest.sentry.io:port/1"
+
+	lookup := func(key string) (string, bool) {
+		return dsn, key == "SENTRY_DSN"
+	}
+
+	var sentryConfig otelpresets.Sentry
+
+	err := config.LoadStruct(&sentryConfig, config.WithPrefix("SENTRY_"), config.WithLookup(lookup))
+	require.ErrorIs(t, err, otelpresets.ErrInvalidSentryDSN)
+	require.NotContains(t, err.Error(), "secret-key")
+
+	sentryConfig = otelpresets.Sentry{DSN: "https://public@o1.ingest.sentry.io/1"}
+	require.NoError(t, sentryConfig.Validate())
+}
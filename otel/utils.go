@@ -6,6 +6,7 @@ import (
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -19,6 +20,12 @@ func Tracer(options ...trace.TracerOption) trace.Tracer {
 	return otel.GetTracerProvider().Tracer(AppName, options...)
 }
 
+// Meter returns the meter of the application, from the provider set by Init. Meters created before Init forward
+// their measurements once it is called. Without Init, measurements are dropped.
+func Meter(options ...metric.MeterOption) metric.Meter {
+	return otel.GetMeterProvider().Meter(AppName, options...)
+}
+
 func Logger(options ...otelslog.Option) *slog.Logger {
 	return otelslog.NewLogger(AppName, options...)
 }
@@ -0,0 +1,352 @@
+package postgrescli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/a-novel-kit/golib/postgres"
+)
+
+// MigrationTimeFormat is the format of the timestamp that prefixes migration files.
+const MigrationTimeFormat = "20060102150405"
+
+var (
+	ErrUnknownCommand       = errors.New("unknown command")
+	ErrInvalidMigrationName = errors.New("invalid migration name")
+	ErrConflictingFlags     = errors.New("conflicting flags")
+)
+
+var migrationNameRE = regexp.MustCompile(`^[0-9a-z_\-]+$`)
+
+// MigrateOptions configures Migrate.
+type MigrateOptions struct {
+	// Dir where the create command writes new migration files. Defaults to "migrations".
+	Dir string
+	// Stdout receives the output of the commands. Defaults to os.Stdout.
+	Stdout io.Writer
+	// Stderr receives usage and errors. Defaults to os.Stderr.
+	Stderr io.Writer
+	// Now returns the time used to name new migrations. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Migrate runs a migration command, from the arguments of a program without its name. The migrations filesystem is
+// usually embedded in the binary, while Dir points to the same directory in the source tree.
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	func main() {
+//		config := postgrespresets.NewDefault(pgdriver.WithDSN(os.Getenv("POSTGRES_DSN")))
+//
+//		err := postgrescli.Migrate(ctx, config, migrations, os.Args[1:], postgrescli.MigrateOptions{})
+//		if err != nil {
+//			os.Exit(1)
+//		}
+//	}
+//
+// Supported commands:
+//
+//	up [-dry-run]          apply pending migrations, or print their SQL
+//	down [-to NAME | -all] roll back the last group of migrations, every migration after NAME, or every migration
+//	status                 list migrations and their status
+//	create NAME            create empty up and down migration files
+//	lock                   prevent migrations from being applied or rolled back
+//	unlock                 release the lock
+//
+// Commands that connect to the database close the connections of config once they are done. Errors are also written
+// to Stderr.
+func Migrate(
+	ctx context.Context, config postgres.Config, migrations fs.FS, args []string, options MigrateOptions,
+) error {
+	options = withDefaults(options)
+
+	err := migrate(ctx, config, migrations, args, options)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		_, _ = fmt.Fprintln(options.Stderr, "error:", err)
+	}
+
+	return err
+}
+
+func migrate(
+	ctx context.Context, config postgres.Config, migrations fs.FS, args []string, options MigrateOptions,
+) error {
+	if len(args) == 0 {
+		printUsage(options.Stderr)
+
+		return fmt.Errorf("%w: missing command", ErrUnknownCommand)
+	}
+
+	command, args := args[0], args[1:]
+
+	flagSet := flag.NewFlagSet(command, flag.ContinueOnError)
+	flagSet.SetOutput(options.Stderr)
+
+	switch command {
+	case "up":
+		dryRun := flagSet.Bool("dry-run", false, "Print the SQL of pending migrations without applying them.")
+
+		return withDB(ctx, config, flagSet, args, func(db *bun.DB) error {
+			if *dryRun {
+				return postgres.DryRunMigrations(ctx, db, migrations, options.Stdout)
+			}
+
+			applied, err := postgres.ApplyMigrations(ctx, db, migrations)
+			if err != nil {
+				return err
+			}
+
+			return printMigrations(options.Stdout, "applied", applied)
+		})
+	case "down":
+		target := flagSet.String("to", "", "Roll back every migration applied after this one.")
+		all := flagSet.Bool("all", false, "Roll back every migration.")
+
+		err := parseArgs(flagSet, args)
+		if err != nil {
+			return err
+		}
+
+		if *target != "" && *all {
+			return fmt.Errorf("%w: -to and -all cannot be used together", ErrConflictingFlags)
+		}
+
+		return connect(ctx, config, func(db *bun.DB) error {
+			var (
+				rolledBack []string
+				err        error
+			)
+
+			if *target != "" || *all {
+				rolledBack, err = postgres.RollbackMigrationsTo(ctx, db, migrations, *target)
+			} else {
+				rolledBack, err = postgres.RollbackMigrations(ctx, db, migrations)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return printMigrations(options.Stdout, "rolled back", rolledBack)
+		})
+	case "status":
+		return withDB(ctx, config, flagSet, args, func(db *bun.DB) error {
+			statuses, err := postgres.MigrationsStatus(ctx, db, migrations)
+			if err != nil {
+				return err
+			}
+
+			return printStatus(options.Stdout, statuses)
+		})
+	case "create":
+		err := flagSet.Parse(args)
+		if err != nil {
+			return err
+		}
+
+		if flagSet.NArg() != 1 {
+			return fmt.Errorf("%w: create expects a single name", ErrInvalidMigrationName)
+		}
+
+		return create(options, flagSet.Arg(0))
+	case "lock":
+		return withDB(ctx, config, flagSet, args, func(db *bun.DB) error {
+			return postgres.LockMigrations(ctx, db)
+		})
+	case "unlock":
+		return withDB(ctx, config, flagSet, args, func(db *bun.DB) error {
+			return postgres.UnlockMigrations(ctx, db)
+		})
+	case "help", "-h", "-help", "--help":
+		printUsage(options.Stderr)
+
+		return flag.ErrHelp
+	default:
+		printUsage(options.Stderr)
+
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, command)
+	}
+}
+
+// withDB parses the flags of a command, then runs it with the main database connection.
+func withDB(
+	ctx context.Context, config postgres.Config, flagSet *flag.FlagSet, args []string, run func(db *bun.DB) error,
+) error {
+	err := parseArgs(flagSet, args)
+	if err != nil {
+		return err
+	}
+
+	return connect(ctx, config, run)
+}
+
+// parseArgs parses the flags of a command that takes no positional argument.
+func parseArgs(flagSet *flag.FlagSet, args []string) error {
+	err := flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if flagSet.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", ErrUnknownCommand, flagSet.Args())
+	}
+
+	return nil
+}
+
+// connect runs a command with the main database connection, and closes the connections of the config once it is
+// done.
+func connect(ctx context.Context, config postgres.Config, run func(db *bun.DB) error) error {
+	db, err := config.DB(ctx)
+	if err != nil {
+		return fmt.Errorf("get db from config: %w", err)
+	}
+
+	err = run(db)
+
+	closeErr := config.Close()
+	if closeErr != nil {
+		closeErr = fmt.Errorf("close db: %w", closeErr)
+	}
+
+	return errors.Join(err, closeErr)
+}
+
+// create writes empty up and down files for a new migration. If a file cannot be created, the files created before
+// it are removed.
+func create(options MigrateOptions, name string) error {
+	if !migrationNameRE.MatchString(name) {
+		return fmt.Errorf("%w: %q must only contain lowercase letters, digits, _ and -", ErrInvalidMigrationName, name)
+	}
+
+	err := os.MkdirAll(options.Dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("create migrations directory: %w", err)
+	}
+
+	prefix := options.Now().UTC().Format(MigrationTimeFormat) + "_" + name
+	files := []string{filepath.Join(options.Dir, prefix+".up.sql"), filepath.Join(options.Dir, prefix+".down.sql")}
+
+	for i, file := range files {
+		err = createFile(file)
+		if err != nil {
+			for _, created := range files[:i] {
+				_ = os.Remove(created)
+			}
+
+			return fmt.Errorf("create migration file: %w", err)
+		}
+	}
+
+	for _, file := range files {
+		_, _ = fmt.Fprintln(options.Stdout, "created", file)
+	}
+
+	return nil
+}
+
+// createFile creates an empty file. O_EXCL prevents overwriting a migration created in the same second.
+func createFile(file string) error {
+	handle, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = handle.Close()
+	if err != nil {
+		_ = os.Remove(file)
+
+		return err
+	}
+
+	return nil
+}
+
+func printMigrations(w io.Writer, action string, names []string) error {
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(w, "no migration "+action)
+
+		return err
+	}
+
+	for _, name := range names {
+		_, err := fmt.Fprintln(w, action, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printStatus(w io.Writer, statuses []*postgres.MigrationStatus) error {
+	var builder strings.Builder
+
+	table := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+
+	_, _ = table.Write([]byte("MIGRATION\tSTATUS\tGROUP\tMIGRATED AT\n"))
+	for _, status := range statuses {
+		state, group, migratedAt := "pending", "-", "-"
+		if status.Applied {
+			state = "applied"
+			group = fmt.Sprint(status.GroupID)
+			migratedAt = status.MigratedAt.UTC().Format(time.RFC3339)
+		}
+
+		_, _ = table.Write([]byte(
+			status.Name + "_" + status.Comment + "\t" + state + "\t" + group + "\t" + migratedAt + "\n",
+		))
+	}
+
+	_ = table.Flush()
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+func printUsage(w io.Writer) {
+	_, _ = io.WriteString(w, `Usage: <command> [flags]
+
+Commands:
+  up [-dry-run]           apply pending migrations, or print their SQL
+  down [-to NAME | -all]  roll back the last group of migrations, every migration after NAME, or every migration
+  status                  list migrations and their status
+  create NAME             create empty up and down migration files
+  lock                    prevent migrations from being applied or rolled back
+  unlock                  release the lock
+`)
+}
+
+func withDefaults(options MigrateOptions) MigrateOptions {
+	if options.Dir == "" {
+		options.Dir = "migrations"
+	}
+
+	if options.Stdout == nil {
+		options.Stdout = os.Stdout
+	}
+
+	if options.Stderr == nil {
+		options.Stderr = os.Stderr
+	}
+
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return options
+}
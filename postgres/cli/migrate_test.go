@@ -0,0 +1,190 @@
+package postgrescli_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	postgrescli "github.com/a-novel-kit/golib/postgres/cli"
+)
+
+// noDBConfig fails the test if a command connects to the database.
+type noDBConfig struct {
+	t *testing.T
+}
+
+func (config noDBConfig) DB(context.Context) (*bun.DB, error) {
+	config.t.Error("unexpected database connection")
+
+	return nil, os.ErrInvalid
+}
+
+func (config noDBConfig) DBSchema(context.Context, string, bool) (*bun.DB, error) {
+	return config.DB(context.Background())
+}
+
+func (config noDBConfig) Close() error {
+	return nil
+}
+
+// closeConfig connects to an unreachable database, and records whether it was closed.
+type closeConfig struct {
+	db     *bun.DB
+	closed *atomic.Bool
+}
+
+func (config closeConfig) DB(context.Context) (*bun.DB, error) {
+	return config.db, nil
+}
+
+func (config closeConfig) DBSchema(context.Context, string, bool) (*bun.DB, error) {
+	return config.db, nil
+}
+
+func (config closeConfig) Close() error {
+	config.closed.Store(true)
+
+	return nil
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "migrations")
+
+		var stdout, stderr bytes.Buffer
+
+		options := postgrescli.MigrateOptions{
+			Dir:    dir,
+			Stdout: &stdout,
+			Stderr: &stderr,
+			Now:    func() time.Time { return now },
+		}
+
+		err := postgrescli.Migrate(t.Context(), noDBConfig{t: t}, fstest.MapFS{}, []string{"create", "users"}, options)
+		require.NoError(t, err)
+
+		for _, file := range []string{"20250304050607_users.up.sql", "20250304050607_users.down.sql"} {
+			require.FileExists(t, filepath.Join(dir, file))
+			require.Contains(t, stdout.String(), file)
+		}
+
+		// Files of the same second are not overwritten.
+		err = postgrescli.Migrate(t.Context(), noDBConfig{t: t}, fstest.MapFS{}, []string{"create", "users"}, options)
+		require.ErrorIs(t, err, os.ErrExist)
+		require.Contains(t, stderr.String(), "error:")
+	})
+
+	t.Run("CreateCleanup", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		down := filepath.Join(dir, "20250304050607_posts.down.sql")
+		require.NoError(t, os.WriteFile(down, nil, 0o644))
+
+		options := postgrescli.MigrateOptions{
+			Dir:    dir,
+			Stdout: &bytes.Buffer{},
+			Stderr: &bytes.Buffer{},
+			Now:    func() time.Time { return now },
+		}
+
+		// The up file is removed if the down file cannot be created.
+		err := postgrescli.Migrate(t.Context(), noDBConfig{t: t}, fstest.MapFS{}, []string{"create", "posts"}, options)
+		require.ErrorIs(t, err, os.ErrExist)
+		require.NoFileExists(t, filepath.Join(dir, "20250304050607_posts.up.sql"))
+		require.FileExists(t, down)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		// Nothing listens on port 1, so the command fails.
+		sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithAddr("127.0.0.1:1"), pgdriver.WithTimeout(time.Second)))
+		config := closeConfig{db: bun.NewDB(sqldb, pgdialect.New()), closed: &atomic.Bool{}}
+
+		t.Cleanup(func() { _ = config.db.Close() })
+
+		err := postgrescli.Migrate(t.Context(), config, fstest.MapFS{}, []string{"unlock"},
+			postgrescli.MigrateOptions{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}})
+		require.Error(t, err)
+		require.True(t, config.closed.Load())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name string
+
+			args []string
+
+			expectErr error
+		}{
+			{
+				name:      "NoCommand",
+				expectErr: postgrescli.ErrUnknownCommand,
+			},
+			{
+				name:      "UnknownCommand",
+				args:      []string{"sideways"},
+				expectErr: postgrescli.ErrUnknownCommand,
+			},
+			{
+				name:      "Help",
+				args:      []string{"help"},
+				expectErr: flag.ErrHelp,
+			},
+			{
+				name:      "InvalidName",
+				args:      []string{"create", "Add Users"},
+				expectErr: postgrescli.ErrInvalidMigrationName,
+			},
+			{
+				name:      "MissingName",
+				args:      []string{"create"},
+				expectErr: postgrescli.ErrInvalidMigrationName,
+			},
+			{
+				name:      "ConflictingFlags",
+				args:      []string{"down", "-to", "20240101120000", "-all"},
+				expectErr: postgrescli.ErrConflictingFlags,
+			},
+			{
+				name:      "UnexpectedArgument",
+				args:      []string{"status", "now"},
+				expectErr: postgrescli.ErrUnknownCommand,
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				var stderr bytes.Buffer
+
+				err := postgrescli.Migrate(t.Context(), noDBConfig{t: t}, fstest.MapFS{}, testCase.args,
+					postgrescli.MigrateOptions{Dir: t.TempDir(), Stdout: &bytes.Buffer{}, Stderr: &stderr})
+				require.ErrorIs(t, err, testCase.expectErr)
+				require.NotEmpty(t, stderr.String())
+			})
+		}
+	})
+}
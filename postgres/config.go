@@ -9,4 +9,15 @@ import (
 type Config interface {
 	DB(ctx context.Context) (*bun.DB, error)
 	DBSchema(ctx context.Context, schema string, create bool) (*bun.DB, error)
+	// Close closes every connection opened by the configuration.
+	Close() error
+}
+
+// SchemaReleaser is implemented by the configurations that track the connections returned by DBSchema. It is
+// optional, so the helpers of this package check for it.
+type SchemaReleaser interface {
+	// ReleaseSchema signals that a connection returned by DBSchema is not used anymore, so it can be closed.
+	ReleaseSchema(schema string)
+	// DropSchema closes the connection of a schema, and drops the schema from the database.
+	DropSchema(ctx context.Context, schema string) error
 }
@@ -22,6 +22,8 @@ func NewContext(ctx context.Context, config Config) (context.Context, error) {
 	return context.WithValue(ctx, ContextKey{}, db), nil
 }
 
+// NewContextSchema returns a context with the connection of a schema. If config implements SchemaReleaser, call
+// ReleaseSchema once the context is not used anymore, so the connection can be evicted.
 func NewContextSchema(ctx context.Context, config Config, schema string, create bool) (context.Context, error) {
 	db, err := config.DBSchema(ctx, schema, create)
 	if err != nil {
@@ -4,38 +4,263 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/migrate"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/a-novel-kit/golib/otel"
 )
 
-var ErrNoDbInContext = errors.New("context does not contain a bun.DB")
+// MigrationLockID is the key of the advisory lock held while migrations are applied or rolled back. It is combined
+// with the hash of the current schema, so schemas can be migrated concurrently.
+const MigrationLockID int32 = 0x6d696772
+
+// Default tables of the bun migrator, where applied migrations and the locks set by LockMigrations are stored.
+const (
+	migrationsTable     = "bun_migrations"
+	migrationLocksTable = "bun_migration_locks"
+)
+
+var (
+	ErrNoDbInContext    = errors.New("context does not contain a bun.DB")
+	ErrMigrationsLocked = errors.New("migrations are locked")
+	ErrUnknownMigration = errors.New("unknown migration")
+)
+
+// MigrationStatus describes a migration found in the migrations filesystem.
+type MigrationStatus struct {
+	// Name is the timestamp of the migration, e.g. "20240101120000".
+	Name string
+	// Comment is the part of the file name that follows the timestamp.
+	Comment string
+	// Applied is true if the migration was applied to the database.
+	Applied bool
+	// GroupID of the batch the migration was applied with. Rolling back the last group reverts every migration
+	// of that batch.
+	GroupID int64
+	// MigratedAt is the time the migration was applied, if it was.
+	MigratedAt time.Time
+}
 
 // RunMigrations runs all the migrations found in the provided filesystem.
 func RunMigrations(ctx context.Context, db *bun.DB, migrations fs.FS) error {
-	ctx, span := otel.Tracer().Start(ctx, "postgres.RunMigrations")
+	_, err := ApplyMigrations(ctx, db, migrations)
+
+	return err
+}
+
+// ApplyMigrations runs all the pending migrations found in the provided filesystem, and returns the names of the
+// migrations that were applied. Concurrent calls on the same schema wait for each other.
+func ApplyMigrations(ctx context.Context, db *bun.DB, migrations fs.FS) ([]string, error) {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.ApplyMigrations")
 	defer span.End()
 
-	mig := migrate.NewMigrations()
+	migrator, err := newMigrator(ctx, db, migrations)
+	if err != nil {
+		return nil, otel.ReportError(span, err)
+	}
+
+	var group *migrate.MigrationGroup
+
+	err = runLocked(ctx, db, func(ctx context.Context) error {
+		group, err = migrator.Migrate(ctx)
 
-	err := mig.Discover(migrations)
+		return err
+	})
 	if err != nil {
-		return otel.ReportError(span, fmt.Errorf("discover mig: %w", err))
+		return nil, otel.ReportError(span, fmt.Errorf("apply mig: %w", err))
 	}
 
-	migrator := migrate.NewMigrator(db, mig)
+	applied := migrationNames(group.Migrations)
+	span.SetAttributes(attribute.StringSlice("migrations", applied))
+
+	return otel.ReportSuccess(span, applied), nil
+}
+
+// RunMigrationsContext runs all the migrations found in the provided filesystem,
+// using the database connection from the context.
+func RunMigrationsContext(ctx context.Context, migrations fs.FS) error {
+	db, err := contextDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	return RunMigrations(ctx, db, migrations)
+}
+
+// MigrationsStatus lists the migrations found in the provided filesystem, sorted by name, with their status in the
+// database.
+func MigrationsStatus(ctx context.Context, db *bun.DB, migrations fs.FS) ([]*MigrationStatus, error) {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.MigrationsStatus")
+	defer span.End()
+
+	migrator, err := newMigrator(ctx, db, migrations)
+	if err != nil {
+		return nil, otel.ReportError(span, err)
+	}
+
+	withStatus, err := migrator.MigrationsWithStatus(ctx)
+	if err != nil {
+		return nil, otel.ReportError(span, fmt.Errorf("get mig status: %w", err))
+	}
+
+	statuses := make([]*MigrationStatus, len(withStatus))
+	for i, migration := range withStatus {
+		statuses[i] = &MigrationStatus{
+			Name:       migration.Name,
+			Comment:    migration.Comment,
+			Applied:    migration.IsApplied(),
+			GroupID:    migration.GroupID,
+			MigratedAt: migration.MigratedAt,
+		}
+	}
+
+	return otel.ReportSuccess(span, statuses), nil
+}
+
+// RollbackMigrations reverts the last group of applied migrations, and returns the names of the migrations that
+// were rolled back, most recent first.
+func RollbackMigrations(ctx context.Context, db *bun.DB, migrations fs.FS) ([]string, error) {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.RollbackMigrations")
+	defer span.End()
+
+	migrator, err := newMigrator(ctx, db, migrations)
+	if err != nil {
+		return nil, otel.ReportError(span, err)
+	}
+
+	var group *migrate.MigrationGroup
+
+	err = runLocked(ctx, db, func(ctx context.Context) error {
+		group, err = migrator.Rollback(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, otel.ReportError(span, fmt.Errorf("rollback mig: %w", err))
+	}
+
+	rolledBack := migrationNames(group.Migrations)
+	slices.Reverse(rolledBack)
+
+	span.SetAttributes(attribute.StringSlice("migrations", rolledBack))
+
+	return otel.ReportSuccess(span, rolledBack), nil
+}
+
+// RollbackMigrationsTo reverts every applied migration whose name is greater than target, most recent first, and
+// returns their names. The target migration itself stays applied. An empty target reverts every migration, any
+// other target must name a migration of the filesystem, e.g. "20240101120000" or "20240101120000_users".
+func RollbackMigrationsTo(ctx context.Context, db *bun.DB, migrations fs.FS, target string) ([]string, error) {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.RollbackMigrationsTo")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("target", target))
+
+	migrator, err := newMigrator(ctx, db, migrations)
+	if err != nil {
+		return nil, otel.ReportError(span, err)
+	}
+
+	var rolledBack []string
 
-	err = migrator.Init(ctx)
+	err = runLocked(ctx, db, func(ctx context.Context) error {
+		withStatus, err := migrator.MigrationsWithStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("get mig status: %w", err)
+		}
+
+		if target != "" {
+			index := slices.IndexFunc(withStatus, func(migration migrate.Migration) bool {
+				return migration.Name == target || migration.String() == target
+			})
+			if index < 0 {
+				return fmt.Errorf("%w: %s", ErrUnknownMigration, target)
+			}
+
+			// Compare names, so the comment of the target does not change which migrations are reverted.
+			target = withStatus[index].Name
+		}
+
+		// Applied migrations are sorted in descending order.
+		for _, migration := range withStatus.Applied() {
+			if migration.Name <= target {
+				break
+			}
+
+			if migration.Down != nil {
+				err = migration.Down(ctx, migrator, &migration)
+				if err != nil {
+					return fmt.Errorf("revert %s: %w", migration.String(), err)
+				}
+			}
+
+			err = migrator.MarkUnapplied(ctx, &migration)
+			if err != nil {
+				return fmt.Errorf("mark %s as unapplied: %w", migration.String(), err)
+			}
+
+			rolledBack = append(rolledBack, migration.String())
+		}
+
+		return nil
+	})
+
+	span.SetAttributes(attribute.StringSlice("migrations", rolledBack))
+
+	if err != nil {
+		return rolledBack, otel.ReportError(span, fmt.Errorf("rollback mig: %w", err))
+	}
+
+	return otel.ReportSuccess(span, rolledBack), nil
+}
+
+// DryRunMigrations writes the SQL of the pending migrations to w, in the order they would be applied, without
+// applying them.
+func DryRunMigrations(ctx context.Context, db *bun.DB, migrations fs.FS, w io.Writer) error {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.DryRunMigrations")
+	defer span.End()
+
+	migrator, err := newMigrator(ctx, db, migrations)
 	if err != nil {
-		return otel.ReportError(span, fmt.Errorf("create migrator: %w", err))
+		return otel.ReportError(span, err)
 	}
 
-	_, err = migrator.Migrate(ctx)
+	withStatus, err := migrator.MigrationsWithStatus(ctx)
 	if err != nil {
-		return otel.ReportError(span, fmt.Errorf("apply mig: %w", err))
+		return otel.ReportError(span, fmt.Errorf("get mig status: %w", err))
+	}
+
+	var builder strings.Builder
+
+	for _, migration := range withStatus.Unapplied() {
+		files, err := migrationFiles(migrations, migration.String(), ".up.sql")
+		if err != nil {
+			return otel.ReportError(span, fmt.Errorf("read %s: %w", migration.String(), err))
+		}
+
+		for _, file := range files {
+			content, err := fs.ReadFile(migrations, file)
+			if err != nil {
+				return otel.ReportError(span, fmt.Errorf("read %s: %w", file, err))
+			}
+
+			builder.WriteString("-- " + file + "\n")
+			builder.WriteString(strings.TrimRight(string(content), "\n") + "\n\n")
+		}
+	}
+
+	_, err = io.WriteString(w, builder.String())
+	if err != nil {
+		return otel.ReportError(span, fmt.Errorf("write sql: %w", err))
 	}
 
 	otel.ReportSuccessNoContent(span)
@@ -43,18 +268,167 @@ func RunMigrations(ctx context.Context, db *bun.DB, migrations fs.FS) error {
 	return nil
 }
 
-// RunMigrationsContext runs all the migrations found in the provided filesystem,
-// using the database connection from the context.
-func RunMigrationsContext(ctx context.Context, migrations fs.FS) error {
+// WithMigrationLock runs callback while holding the advisory lock of the current schema, so replicas starting at
+// the same time do not apply migrations concurrently. It waits until the lock is available, or ctx is canceled.
+func WithMigrationLock(ctx context.Context, db *bun.DB, callback func(ctx context.Context) error) error {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.WithMigrationLock")
+	defer span.End()
+
+	// Advisory locks belong to a session, so they must be acquired and released on the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return otel.ReportError(span, fmt.Errorf("get connection: %w", err))
+	}
+
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock(?, hashtext(current_schema()))", MigrationLockID)
+	if err != nil {
+		return otel.ReportError(span, fmt.Errorf("acquire migration lock: %w", err))
+	}
+
+	err = callback(ctx)
+
+	_, unlockErr := conn.ExecContext(
+		context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(?, hashtext(current_schema()))", MigrationLockID,
+	)
+	if unlockErr != nil {
+		err = errors.Join(err, fmt.Errorf("release migration lock: %w", unlockErr))
+	}
+
+	if err != nil {
+		return otel.ReportError(span, err)
+	}
+
+	otel.ReportSuccessNoContent(span)
+
+	return nil
+}
+
+// LockMigrations prevents migrations from being applied or rolled back on the current schema, until
+// UnlockMigrations is called. Unlike WithMigrationLock, the lock is stored in the database, and outlives the
+// connection.
+func LockMigrations(ctx context.Context, db *bun.DB) error {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.LockMigrations")
+	defer span.End()
+
+	migrator, err := newMigrator(ctx, db, nil)
+	if err != nil {
+		return otel.ReportError(span, err)
+	}
+
+	err = migrator.Lock(ctx)
+	if err != nil {
+		return otel.ReportError(span, fmt.Errorf("%w: %w", ErrMigrationsLocked, err))
+	}
+
+	otel.ReportSuccessNoContent(span)
+
+	return nil
+}
+
+// UnlockMigrations releases the lock set by LockMigrations. It is a no-op if migrations are not locked.
+func UnlockMigrations(ctx context.Context, db *bun.DB) error {
+	ctx, span := otel.Tracer().Start(ctx, "postgres.UnlockMigrations")
+	defer span.End()
+
+	migrator, err := newMigrator(ctx, db, nil)
+	if err != nil {
+		return otel.ReportError(span, err)
+	}
+
+	err = migrator.Unlock(ctx)
+	if err != nil {
+		return otel.ReportError(span, fmt.Errorf("unlock migrations: %w", err))
+	}
+
+	otel.ReportSuccessNoContent(span)
+
+	return nil
+}
+
+// runLocked runs callback while holding the advisory lock, and fails if migrations were locked with
+// LockMigrations. The advisory lock already serializes migrations, so the lock table is only read: a process
+// killed while migrating must not leave migrations locked.
+func runLocked(ctx context.Context, db *bun.DB, callback func(ctx context.Context) error) error {
+	return WithMigrationLock(ctx, db, func(ctx context.Context) error {
+		locked, err := db.NewSelect().
+			TableExpr(migrationLocksTable).
+			Where("? = ?", bun.Ident("table_name"), migrationsTable).
+			Exists(ctx)
+		if err != nil {
+			return fmt.Errorf("check migration lock: %w", err)
+		}
+
+		if locked {
+			return fmt.Errorf("%w: run UnlockMigrations to release them", ErrMigrationsLocked)
+		}
+
+		return callback(ctx)
+	})
+}
+
+// newMigrator returns an initialized migrator. Migrations are only discovered if the filesystem is not nil.
+func newMigrator(ctx context.Context, db *bun.DB, migrations fs.FS) (*migrate.Migrator, error) {
+	mig := migrate.NewMigrations()
+
+	if migrations != nil {
+		err := mig.Discover(migrations)
+		if err != nil {
+			return nil, fmt.Errorf("discover mig: %w", err)
+		}
+	}
+
+	migrator := migrate.NewMigrator(db, mig)
+
+	err := migrator.Init(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create migrator: %w", err)
+	}
+
+	return migrator, nil
+}
+
+// migrationFiles returns the files of a migration with the given suffix, e.g. ".up.sql" for both
+// 20240101120000_users.up.sql and 20240101120000_users.tx.up.sql.
+func migrationFiles(migrations fs.FS, name, suffix string) ([]string, error) {
+	var files []string
+
+	err := fs.WalkDir(migrations, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := path.Base(file)
+		if !entry.IsDir() && strings.HasPrefix(base, name+".") && strings.HasSuffix(base, suffix) {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+func migrationNames(migrations migrate.MigrationSlice) []string {
+	names := make([]string, len(migrations))
+	for i, migration := range migrations {
+		names[i] = migration.String()
+	}
+
+	return names
+}
+
+func contextDB(ctx context.Context) (*bun.DB, error) {
 	tx, err := GetContext(ctx)
 	if err != nil {
-		return fmt.Errorf("get db from context: %w", err)
+		return nil, fmt.Errorf("get db from context: %w", err)
 	}
 
 	db, ok := tx.(*bun.DB)
 	if !ok {
-		return ErrNoDbInContext
+		return nil, ErrNoDbInContext
 	}
 
-	return RunMigrations(ctx, db, migrations)
+	return db, nil
 }
@@ -0,0 +1,225 @@
+package postgres_test
+
+import (
+	"context"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"github.com/a-novel-kit/golib/postgres"
+	postgrespresets "github.com/a-novel-kit/golib/postgres/presets"
+)
+
+var firstMigrations = fstest.MapFS{
+	"20240101000000_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);\n")},
+	"20240101000000_users.down.sql": {Data: []byte("DROP TABLE users;\n")},
+	"20240102000000_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INT);\n")},
+	"20240102000000_posts.down.sql": {Data: []byte("DROP TABLE posts;\n")},
+}
+
+var allMigrations = withMigrations(firstMigrations, fstest.MapFS{
+	"20240103000000_comments.tx.up.sql":   {Data: []byte("CREATE TABLE comments (id INT);\n")},
+	"20240103000000_comments.tx.down.sql": {Data: []byte("DROP TABLE comments;\n")},
+})
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Status", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "status")
+
+		_, err := postgres.ApplyMigrations(t.Context(), db, firstMigrations)
+		require.NoError(t, err)
+
+		statuses, err := postgres.MigrationsStatus(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+		require.Len(t, statuses, 3)
+
+		for i, expect := range []struct {
+			name    string
+			comment string
+			applied bool
+		}{
+			{name: "20240101000000", comment: "users", applied: true},
+			{name: "20240102000000", comment: "posts", applied: true},
+			{name: "20240103000000", comment: "comments"},
+		} {
+			require.Equal(t, expect.name, statuses[i].Name)
+			require.Equal(t, expect.comment, statuses[i].Comment)
+			require.Equal(t, expect.applied, statuses[i].Applied)
+
+			if expect.applied {
+				require.Equal(t, int64(1), statuses[i].GroupID)
+				require.False(t, statuses[i].MigratedAt.IsZero())
+			}
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "rollback")
+
+		applied, err := postgres.ApplyMigrations(t.Context(), db, firstMigrations)
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240101000000_users", "20240102000000_posts"}, applied)
+
+		applied, err = postgres.ApplyMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240103000000_comments"}, applied)
+
+		// Each call reverts a single group.
+		rolledBack, err := postgres.RollbackMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240103000000_comments"}, rolledBack)
+		require.Equal(t, []string{"posts", "users"}, tables(t, db))
+
+		rolledBack, err = postgres.RollbackMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240102000000_posts", "20240101000000_users"}, rolledBack)
+		require.Empty(t, tables(t, db))
+	})
+
+	t.Run("RollbackTo", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "rollback_to")
+
+		_, err := postgres.ApplyMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+
+		_, err = postgres.RollbackMigrationsTo(t.Context(), db, allMigrations, "20240101")
+		require.ErrorIs(t, err, postgres.ErrUnknownMigration)
+		require.Equal(t, []string{"comments", "posts", "users"}, tables(t, db))
+
+		rolledBack, err := postgres.RollbackMigrationsTo(t.Context(), db, allMigrations, "20240101000000_users")
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240103000000_comments", "20240102000000_posts"}, rolledBack)
+		require.Equal(t, []string{"users"}, tables(t, db))
+
+		rolledBack, err = postgres.RollbackMigrationsTo(t.Context(), db, allMigrations, "20240101000000")
+		require.NoError(t, err)
+		require.Empty(t, rolledBack)
+
+		rolledBack, err = postgres.RollbackMigrationsTo(t.Context(), db, allMigrations, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240101000000_users"}, rolledBack)
+		require.Empty(t, tables(t, db))
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "dry_run")
+
+		_, err := postgres.ApplyMigrations(t.Context(), db, firstMigrations)
+		require.NoError(t, err)
+
+		var output strings.Builder
+
+		// Only the up files of pending migrations are printed, including transactional ones.
+		require.NoError(t, postgres.DryRunMigrations(t.Context(), db, allMigrations, &output))
+		require.Equal(t, "-- 20240103000000_comments.tx.up.sql\nCREATE TABLE comments (id INT);\n\n", output.String())
+		require.Equal(t, []string{"posts", "users"}, tables(t, db))
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "lock")
+
+		require.NoError(t, postgres.LockMigrations(t.Context(), db))
+
+		_, err := postgres.ApplyMigrations(t.Context(), db, allMigrations)
+		require.ErrorIs(t, err, postgres.ErrMigrationsLocked)
+
+		require.NoError(t, postgres.UnlockMigrations(t.Context(), db))
+		require.NoError(t, postgres.UnlockMigrations(t.Context(), db))
+
+		_, err = postgres.ApplyMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+
+		// Running migrations does not leave a lock behind.
+		_, err = postgres.RollbackMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+	})
+
+	t.Run("WithMigrationLock", func(t *testing.T) {
+		t.Parallel()
+
+		db := newMigrationsDB(t, "with_lock")
+
+		err := postgres.WithMigrationLock(t.Context(), db, func(ctx context.Context) error {
+			// Migrations wait for the advisory lock, held on another connection.
+			ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+			defer cancel()
+
+			_, err := postgres.ApplyMigrations(ctx, db, allMigrations)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		// The lock is released with the callback.
+		_, err = postgres.ApplyMigrations(t.Context(), db, allMigrations)
+		require.NoError(t, err)
+	})
+}
+
+func withMigrations(migrations ...fstest.MapFS) fstest.MapFS {
+	output := fstest.MapFS{}
+	for _, current := range migrations {
+		maps.Copy(output, current)
+	}
+
+	return output
+}
+
+// newMigrationsDB returns a connection to a new schema, dropped at the end of the test.
+func newMigrationsDB(t *testing.T, name string) *bun.DB {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN is not set")
+	}
+
+	config := postgrespresets.NewDefault(pgdriver.WithDSN(dsn))
+	schema := "migrations_" + name
+
+	t.Cleanup(func() {
+		config.ReleaseSchema(schema)
+		require.NoError(t, config.DropSchema(context.Background(), schema))
+		require.NoError(t, config.Close())
+	})
+
+	db, err := config.DBSchema(t.Context(), schema, true)
+	require.NoError(t, err)
+
+	return db
+}
+
+// tables lists the tables created by the test migrations, in the current schema.
+func tables(t *testing.T, db *bun.DB) []string {
+	t.Helper()
+
+	var names []string
+
+	err := db.NewRaw(
+		"SELECT table_name FROM information_schema.tables "+
+			"WHERE table_schema = current_schema() AND table_name NOT LIKE 'bun_%' ORDER BY table_name",
+	).Scan(t.Context(), &names)
+	require.NoError(t, err)
+
+	return names
+}
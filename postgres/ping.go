@@ -3,22 +3,108 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
-const PingTimeout = 10 * time.Second
+const (
+	PingTimeout = 10 * time.Second
+
+	DefaultPingInitialInterval = 100 * time.Millisecond
+	DefaultPingMaxInterval     = 2 * time.Second
+	DefaultPingMultiplier      = 2
+	DefaultPingJitter          = 0.2
+)
+
+// PingConfig configures the retries of PingWithConfig. Zero values use the defaults.
+type PingConfig struct {
+	// Timeout after which pinging fails. Defaults to PingTimeout.
+	Timeout time.Duration `env:"TIMEOUT" json:"timeout" yaml:"timeout"`
+	// InitialInterval between the first two attempts. Defaults to DefaultPingInitialInterval.
+	InitialInterval time.Duration `env:"INITIAL_INTERVAL" json:"initialInterval" yaml:"initialInterval"`
+	// MaxInterval between two attempts. Defaults to DefaultPingMaxInterval.
+	MaxInterval time.Duration `env:"MAX_INTERVAL" json:"maxInterval" yaml:"maxInterval"`
+	// Multiplier applied to the interval after each failed attempt. Defaults to DefaultPingMultiplier.
+	Multiplier float64 `env:"MULTIPLIER" json:"multiplier" yaml:"multiplier"`
+	// Jitter randomizes each interval by up to this fraction of its value, so replicas starting together do not
+	// retry in sync. Defaults to DefaultPingJitter, and is capped to 1. Set a negative value to disable it.
+	Jitter float64 `env:"JITTER" json:"jitter" yaml:"jitter"`
+}
 
 // Ping a database connection until it succeeds or the timeout is reached.
 func Ping(ctx context.Context, client *bun.DB) error {
-	start := time.Now()
+	return PingWithConfig(ctx, client, PingConfig{})
+}
+
+// PingWithConfig pings a database connection until it succeeds, with an exponential backoff between attempts. It
+// fails once the timeout is reached, or the context is canceled.
+func PingWithConfig(ctx context.Context, client *bun.DB, config PingConfig) error {
+	config = config.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	interval := config.InitialInterval
 
-	for err := client.PingContext(ctx); err != nil; err = client.PingContext(ctx) {
-		if time.Since(start) > PingTimeout {
+	for {
+		err := client.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
 			return fmt.Errorf("ping database: %w", err)
 		}
+
+		timer := time.NewTimer(config.jitter(interval))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("ping database: %w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*config.Multiplier), config.MaxInterval)
+	}
+}
+
+func (config PingConfig) withDefaults() PingConfig {
+	if config.Timeout <= 0 {
+		config.Timeout = PingTimeout
+	}
+
+	if config.InitialInterval <= 0 {
+		config.InitialInterval = DefaultPingInitialInterval
+	}
+
+	if config.MaxInterval <= 0 {
+		config.MaxInterval = DefaultPingMaxInterval
+	}
+
+	if config.Multiplier < 1 {
+		config.Multiplier = DefaultPingMultiplier
+	}
+
+	if config.Jitter == 0 {
+		config.Jitter = DefaultPingJitter
+	}
+
+	// A larger jitter could make the interval negative, and retry without waiting.
+	config.Jitter = min(config.Jitter, 1)
+
+	return config
+}
+
+// jitter randomizes an interval by up to ±Jitter of its value.
+func (config PingConfig) jitter(interval time.Duration) time.Duration {
+	if config.Jitter <= 0 {
+		return interval
 	}
 
-	return nil
+	//nolint:gosec // Jitter does not need a secure source.
+	return time.Duration(float64(interval) * (1 + config.Jitter*(2*rand.Float64()-1)))
 }
@@ -0,0 +1,51 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"github.com/a-novel-kit/golib/postgres"
+)
+
+func TestPingWithConfig(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens on port 1, so every attempt fails.
+	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithAddr("127.0.0.1:1"), pgdriver.WithTimeout(time.Second)))
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now()
+
+		err := postgres.PingWithConfig(t.Context(), db, postgres.PingConfig{
+			Timeout:         200 * time.Millisecond,
+			InitialInterval: 10 * time.Millisecond,
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+
+		err := postgres.PingWithConfig(ctx, db, postgres.PingConfig{Timeout: time.Minute})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,140 @@
+package postgrespresets
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"weak"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+var errUnsupportedConn = errors.New("driver connection does not support search_path switching")
+
+type searchPathKey struct{}
+
+// searchPathHook marks the queries of a schema connection, so the search_path of the connection that runs them is
+// set when it is checked out of the shared pool. bun checks out connections with the context returned by
+// BeforeQuery, including for BEGIN.
+type searchPathHook struct {
+	schema string
+}
+
+var _ bun.QueryHook = searchPathHook{}
+
+func (hook searchPathHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return context.WithValue(ctx, searchPathKey{}, hook.schema)
+}
+
+func (hook searchPathHook) AfterQuery(context.Context, *bun.QueryEvent) {}
+
+// searchPaths tracks the search_path of the connections of the shared pool, so it is only changed when a connection
+// switches schema. Connections are not wrapped, as pgdriver expects *pgdriver.Conn for COPY and LISTEN.
+type searchPaths struct {
+	// schemas currently set as search_path, by connection. Empty for the default search_path.
+	schemas map[weak.Pointer[pgdriver.Conn]]string
+
+	mu sync.Mutex
+}
+
+// newSearchPathConnector returns the connector of a pool shared between schemas.
+func newSearchPathConnector(options []pgdriver.Option) driver.Connector {
+	paths := &searchPaths{schemas: make(map[weak.Pointer[pgdriver.Conn]]string)}
+	reset := pgdriver.NewConnector(options...).Config().ResetSessionFunc
+
+	// Connections are reset every time they are checked out of the pool, except the first time.
+	options = append(append([]pgdriver.Option{}, options...), pgdriver.WithResetSessionFunc(
+		func(ctx context.Context, conn *pgdriver.Conn) error {
+			if reset != nil {
+				err := reset(ctx, conn)
+				if err != nil {
+					return err
+				}
+			}
+
+			return paths.use(ctx, conn)
+		},
+	))
+
+	return searchPathConnector{Connector: pgdriver.NewConnector(options...), paths: paths}
+}
+
+// use switches the search_path of a connection to the schema of the context.
+func (paths *searchPaths) use(ctx context.Context, conn *pgdriver.Conn) error {
+	schema, _ := ctx.Value(searchPathKey{}).(string)
+	key := weak.Make(conn)
+
+	paths.mu.Lock()
+	current, known := paths.schemas[key]
+	paths.mu.Unlock()
+
+	// New connections use the default search_path.
+	if current != schema {
+		var err error
+
+		if schema == "" {
+			_, err = conn.ExecContext(ctx, "RESET search_path", nil)
+		} else {
+			// Like the connection parameter of the pool strategy, the value is a list of schemas, not an identifier.
+			_, err = conn.ExecContext(ctx, "SELECT set_config('search_path', $1, false)", []driver.NamedValue{
+				{Ordinal: 1, Value: schema},
+			})
+		}
+
+		if err != nil {
+			return fmt.Errorf("set search_path to %q: %w", schema, err)
+		}
+	}
+
+	paths.mu.Lock()
+	defer paths.mu.Unlock()
+
+	if !known {
+		runtime.AddCleanup(conn, paths.forget, key)
+	}
+
+	paths.schemas[key] = schema
+
+	return nil
+}
+
+func (paths *searchPaths) forget(key weak.Pointer[pgdriver.Conn]) {
+	paths.mu.Lock()
+	defer paths.mu.Unlock()
+
+	delete(paths.schemas, key)
+}
+
+// searchPathConnector sets the search_path of new connections, that are not reset before their first query.
+type searchPathConnector struct {
+	driver.Connector
+
+	paths *searchPaths
+}
+
+func (connector searchPathConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := connector.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	typed, ok := conn.(*pgdriver.Conn)
+	if !ok {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %T", errUnsupportedConn, conn)
+	}
+
+	err = connector.paths.use(ctx, typed)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return typed, nil
+}
@@ -14,16 +14,32 @@ import (
 
 type TransactionalTestFunc func(context.Context, *testing.T)
 
+// NewContextTest returns a context with a connection to a new throwaway schema. If config implements
+// SchemaReleaser, the connection is released once ctx is done, e.g. when the test of t.Context() completes. The schema
+// is not removed: prefer RunIsolatedTransactionalTest, which drops it once the test completes.
 func NewContextTest(ctx context.Context, config Config) (context.Context, error) {
+	ctx, schema, err := newContextTest(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if releaser, ok := config.(SchemaReleaser); ok {
+		context.AfterFunc(ctx, func() { releaser.ReleaseSchema(schema) })
+	}
+
+	return ctx, nil
+}
+
+func newContextTest(ctx context.Context, config Config) (context.Context, string, error) {
 	schemaName := "ta_" + strings.ToLower(rand.Text())
 	schemaName = fmt.Sprintf("%.*s", NameLen, schemaName)
 
 	db, err := config.DBSchema(ctx, schemaName, true)
 	if err != nil {
-		return nil, fmt.Errorf("get db from config: %w", err)
+		return nil, "", fmt.Errorf("get db from config: %w", err)
 	}
 
-	return context.WithValue(ctx, ContextKey{}, db), nil
+	return context.WithValue(ctx, ContextKey{}, db), schemaName, nil
 }
 
 // RunIsolatedTransactionalTest runs test in a temporary throwaway schema. This allows for operations that cannot
@@ -31,12 +47,25 @@ func NewContextTest(ctx context.Context, config Config) (context.Context, error)
 //
 // This method uses a separate schema, rather than a new database, so existing extensions are still available. It
 // still requires to rerun the whole migration process, so unless needed, RunTransactionalTest should be preferred.
+//
+// If config implements SchemaReleaser, the schema is dropped, and its connection released, once the test completes.
 func RunIsolatedTransactionalTest(t *testing.T, config Config, migrations fs.FS, callback TransactionalTestFunc) {
 	t.Helper()
 
-	ctx, err := NewContextTest(t.Context(), config)
+	ctx, schema, err := newContextTest(t.Context(), config)
 	require.NoError(t, err)
 
+	t.Cleanup(func() {
+		releaser, ok := config.(SchemaReleaser)
+		if !ok {
+			return
+		}
+
+		releaser.ReleaseSchema(schema)
+		// The context of the test is canceled before cleanup runs.
+		require.NoError(t, releaser.DropSchema(context.Background(), schema))
+	})
+
 	require.NoError(t, RunMigrationsContext(ctx, migrations))
 
 	db, err := GetContext(ctx)
@@ -8,15 +8,17 @@ import (
 	"text/template"
 )
 
+var ErrMissingAddr = errors.New("missing SMTP server address")
+
 type ProdSender struct {
-	Addr     string `json:"addr"     yaml:"addr"`
-	Name     string `json:"name"     yaml:"name"`
-	Email    string `json:"email"    yaml:"email"`
-	Password string `json:"password" yaml:"password"`
-	Domain   string `json:"domain"   yaml:"domain"`
+	Addr     string `env:"ADDR"     json:"addr"     yaml:"addr"`
+	Name     string `env:"NAME"     json:"name"     yaml:"name"`
+	Email    string `env:"EMAIL"    json:"email"    yaml:"email"`
+	Password string `env:"PASSWORD" json:"password" secret:"true" yaml:"password"`
+	Domain   string `env:"DOMAIN"   json:"domain"   yaml:"domain"`
 
 	// Dangerous setting, only use for local development.
-	ForceUnencryptedTls bool `json:"forceUnencryptedTLS" yaml:"forceUnencryptedTLS"`
+	ForceUnencryptedTls bool `env:"FORCE_UNENCRYPTED_TLS" json:"forceUnencryptedTLS" yaml:"forceUnencryptedTLS"`
 }
 
 func (sender *ProdSender) SendMail(to MailUsers, t *template.Template, tName string, data any) error {
@@ -44,6 +46,15 @@ func (sender *ProdSender) SendMail(to MailUsers, t *template.Template, tName str
 	return nil
 }
 
+// Validate checks the sender is configured with a server address.
+func (sender *ProdSender) Validate() error {
+	if sender.Addr == "" {
+		return ErrMissingAddr
+	}
+
+	return nil
+}
+
 func (sender *ProdSender) Ping() error {
 	auth := smtp.PlainAuth(sender.Name, sender.Email, sender.Password, sender.Domain)
 	if sender.ForceUnencryptedTls {